@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/metrics"
+)
+
+type Handler struct {
+	pool   *pgxpool.Pool
+	db     *db.DB
+	logger *slog.Logger
+	mux    *http.ServeMux
+}
+
+// NewHandler builds the admin HTTP handler serving health, readiness and metrics endpoints
+func NewHandler(pool *pgxpool.Pool, dbClient *db.DB, logger *slog.Logger) (*Handler, error) {
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	h := &Handler{
+		pool:   pool,
+		db:     dbClient,
+		logger: logger.With("component", "admin"),
+		mux:    http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /healthz", h.healthz)
+	h.mux.HandleFunc("GET /readyz", h.readyz)
+	h.mux.Handle("GET /metrics", metrics.Handler())
+
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// healthz only reports that the process is up and serving requests
+func (h *Handler) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// readyz reports ready once the database is reachable, fully migrated and holds imported data
+func (h *Handler) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.ready(ctx); err != nil {
+		h.logger.Warn("Readiness check failed", "error", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+func (h *Handler) ready(ctx context.Context) error {
+	if err := h.pool.Ping(ctx); err != nil {
+		return errors.New("database unreachable")
+	}
+
+	pending, err := db.PendingMigrations(ctx, h.pool)
+	if err != nil {
+		return errors.New("could not check migrations")
+	}
+	if pending {
+		return errors.New("migrations pending")
+	}
+
+	imports, err := h.db.LastImports(ctx, h.pool)
+	if err != nil {
+		return errors.New("could not check imports")
+	}
+	if len(imports) == 0 {
+		return errors.New("no successful import yet")
+	}
+
+	return nil
+}
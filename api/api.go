@@ -0,0 +1,155 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern/db"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+type Handler struct {
+	pool   *pgxpool.Pool
+	db     *db.DB
+	logger *slog.Logger
+	mux    *http.ServeMux
+}
+
+// NewHandler builds the read-only JSON API over the company registry
+func NewHandler(pool *pgxpool.Pool, dbClient *db.DB, logger *slog.Logger) (*Handler, error) {
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
+	if dbClient == nil {
+		return nil, errors.New("db client required")
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	h := &Handler{
+		pool:   pool,
+		db:     dbClient,
+		logger: logger.With("component", "api"),
+		mux:    http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /openapi.yaml", h.openAPI)
+	h.mux.HandleFunc("GET /stats", h.stats)
+	h.mux.HandleFunc("GET /imports", h.imports)
+	h.mux.HandleFunc("GET /companies", h.searchCompanies)
+	h.mux.HandleFunc("GET /companies/{cui}", h.companyByTaxID)
+	h.mux.HandleFunc("GET /companies/{reg_code}/profile", h.profile)
+	h.mux.HandleFunc("GET /companies/{reg_code}/representatives", h.representatives)
+	h.mux.HandleFunc("GET /companies/{reg_code}/activities", h.activities)
+	h.mux.HandleFunc("GET /companies/{reg_code}/status", h.status)
+	h.mux.HandleFunc("GET /companies/{reg_code}/branches", h.branches)
+	h.mux.HandleFunc("GET /companies/{reg_code}/related", h.related)
+
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Page wraps a list response with the cursor of the following page, empty on the last page
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPISpec)
+}
+
+func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.db.RegistryStats(r.Context(), h.pool)
+	if err != nil {
+		h.logger.Error("Stats lookup failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "stats lookup failed")
+		return
+	}
+
+	stats.ByCounty = nonNil(stats.ByCounty)
+	stats.ByLegalForm = nonNil(stats.ByLegalForm)
+	stats.ByCAENSection = nonNil(stats.ByCAENSection)
+	stats.ByRegistrationYear = nonNil(stats.ByRegistrationYear)
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("Failed to encode response", "error", err)
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, msg string) {
+	h.writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// imports lists the most recent import runs; they are not paginated, only limited
+func (h *Handler) imports(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, err := pagination(r)
+	if err == nil && cursor != nil {
+		err = errors.New("import runs do not support cursor, use limit")
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	runs, err := h.db.ImportRuns(r.Context(), h.pool, r.URL.Query().Get("resource"), limit)
+	if err != nil {
+		h.logger.Error("Import runs lookup failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "import runs lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, nonNil(runs))
+}
+
+// pagination parses the limit and cursor query parameters, applying defaults and bounds
+func pagination(r *http.Request) (limit int, cursor *db.SearchCursor, err error) {
+	limit = defaultLimit
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+			return 0, nil, errors.New("limit must be a positive integer")
+		}
+		limit = min(limit, maxLimit)
+	}
+
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		c, err := db.DecodeSearchCursor(v)
+		if err != nil {
+			return 0, nil, err
+		}
+		cursor = &c
+	}
+
+	return limit, cursor, nil
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+func (h *Handler) searchCompanies(w http.ResponseWriter, r *http.Request) {
+	sq, err := searchQuery(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sq.Term == "" && sq.SearchFilters.IsZero() {
+		h.writeError(w, http.StatusBadRequest, "query parameter q or a filter is required")
+		return
+	}
+
+	results, next, err := h.db.Search(r.Context(), h.pool, sq)
+	if err != nil {
+		h.logger.Error("Search failed", "query", sq.Term, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+
+	page := Page[goovern.Company]{Data: nonNil(results), Limit: sq.Limit}
+	if next != nil {
+		page.NextCursor = next.Encode()
+	}
+	h.writeJSON(w, http.StatusOK, page)
+}
+
+// searchQuery builds a search from q, which accepts the same key:value filter syntax as the TUI,
+// and the structured filter parameters, which take precedence
+func searchQuery(r *http.Request) (db.SearchQuery, error) {
+	params := r.URL.Query()
+
+	sq, err := db.ParseSearchQuery(params.Get("q"))
+	if err != nil {
+		return sq, err
+	}
+
+	if sq.Mode, err = searchMode(r); err != nil {
+		return sq, err
+	}
+	if sq.Limit, sq.After, err = pagination(r); err != nil {
+		return sq, err
+	}
+
+	for param, filter := range map[string]*string{
+		"county":     &sq.County,
+		"locality":   &sq.Locality,
+		"legal_form": &sq.LegalForm,
+		"caen":       &sq.CAEN,
+		"status":     &sq.Status,
+	} {
+		if v := params.Get(param); v != "" {
+			*filter = v
+		}
+	}
+
+	for param, filter := range map[string]*time.Time{
+		"registered_from": &sq.RegisteredFrom,
+		"registered_to":   &sq.RegisteredTo,
+	} {
+		if v := params.Get(param); v != "" {
+			if *filter, err = time.Parse(time.DateOnly, v); err != nil {
+				return sq, fmt.Errorf("%s must be a date (YYYY-MM-DD)", param)
+			}
+		}
+	}
+
+	if v := params.Get("has_website"); v != "" {
+		hasWebsite, err := strconv.ParseBool(v)
+		if err != nil {
+			return sq, errors.New("has_website must be true or false")
+		}
+		sq.HasWebsite = &hasWebsite
+	}
+
+	return sq, nil
+}
+
+// searchMode parses the mode query parameter, defaulting to fuzzy search
+func searchMode(r *http.Request) (db.SearchMode, error) {
+	switch r.URL.Query().Get("mode") {
+	case "", "fuzzy":
+		return db.SearchFuzzy, nil
+	case "fulltext":
+		return db.SearchFullText, nil
+	case "prefix":
+		return db.SearchPrefix, nil
+	default:
+		return 0, errors.New("mode must be one of: fuzzy, fulltext, prefix")
+	}
+}
+
+func (h *Handler) companyByTaxID(w http.ResponseWriter, r *http.Request) {
+	cui := r.PathValue("cui")
+
+	company, found, err := h.db.CompanyByTaxID(r.Context(), h.pool, cui)
+	if err != nil {
+		h.logger.Error("Company lookup failed", "cui", cui, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "company lookup failed")
+		return
+	}
+	if !found {
+		h.writeError(w, http.StatusNotFound, "company not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, company)
+}
+
+func (h *Handler) profile(w http.ResponseWriter, r *http.Request) {
+	regCode := r.PathValue("reg_code")
+
+	profile, found, err := h.db.CompanyProfile(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Profile lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "profile lookup failed")
+		return
+	}
+	if !found {
+		h.writeError(w, http.StatusNotFound, "company not found")
+		return
+	}
+
+	profile.LegalRepresentatives = nonNil(profile.LegalRepresentatives)
+	profile.FamilyBusinessRepresentatives = nonNil(profile.FamilyBusinessRepresentatives)
+	profile.Activities = nonNil(profile.Activities)
+	profile.StatusHistory = nonNil(profile.StatusHistory)
+	profile.ForeignBranches = nonNil(profile.ForeignBranches)
+
+	h.writeJSON(w, http.StatusOK, profile)
+}
+
+// Representatives groups the legal and family business representatives of a company
+type Representatives struct {
+	Legal          []goovern.LegalRepresentative          `json:"legal"`
+	FamilyBusiness []goovern.FamilyBusinessRepresentative `json:"family_business"`
+}
+
+func (h *Handler) representatives(w http.ResponseWriter, r *http.Request) {
+	regCode, ok := h.requireCompany(w, r)
+	if !ok {
+		return
+	}
+
+	legal, err := h.db.LegalRepresentatives(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Legal representatives lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "representatives lookup failed")
+		return
+	}
+
+	family, err := h.db.FamilyBusinessRepresentatives(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Family business representatives lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "representatives lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Representatives{
+		Legal:          nonNil(legal),
+		FamilyBusiness: nonNil(family),
+	})
+}
+
+func (h *Handler) activities(w http.ResponseWriter, r *http.Request) {
+	regCode, ok := h.requireCompany(w, r)
+	if !ok {
+		return
+	}
+
+	activities, err := h.db.AuthorizedActivities(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Activities lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "activities lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, nonNil(activities))
+}
+
+func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
+	regCode, ok := h.requireCompany(w, r)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.db.StatusHistory(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Status lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "status lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, nonNil(statuses))
+}
+
+func (h *Handler) branches(w http.ResponseWriter, r *http.Request) {
+	regCode, ok := h.requireCompany(w, r)
+	if !ok {
+		return
+	}
+
+	branches, err := h.db.ForeignBranches(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Branches lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "branches lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, nonNil(branches))
+}
+
+func (h *Handler) related(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := pagination(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	regCode, ok := h.requireCompany(w, r)
+	if !ok {
+		return
+	}
+
+	related, err := h.db.RelatedCompanies(r.Context(), h.pool, regCode, limit)
+	if err != nil {
+		h.logger.Error("Related companies lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "related companies lookup failed")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, nonNil(related))
+}
+
+// requireCompany resolves the reg_code path value and writes a 404 when no such company exists
+func (h *Handler) requireCompany(w http.ResponseWriter, r *http.Request) (string, bool) {
+	regCode := r.PathValue("reg_code")
+
+	_, found, err := h.db.Company(r.Context(), h.pool, regCode)
+	if err != nil {
+		h.logger.Error("Company lookup failed", "registration_code", regCode, "error", err)
+		h.writeError(w, http.StatusInternalServerError, "company lookup failed")
+		return "", false
+	}
+	if !found {
+		h.writeError(w, http.StatusNotFound, "company not found")
+		return "", false
+	}
+
+	return regCode, true
+}
+
+// nonNil makes empty results encode as [] rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
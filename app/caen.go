@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// caenChrome is the height of everything around the CAEN table: title, breadcrumb, borders, status and help
+const caenChrome = 12
+
+type caenVersionsMsg struct {
+	versions []goovern.CAENVersion
+	err      error
+}
+
+type caenNodesMsg struct {
+	nodes []goovern.CAENNode
+	err   error
+	seq   int
+}
+
+// openCAEN shows the CAEN browser, loading the versions the first time
+func (m *Model) openCAEN() tea.Cmd {
+	m.mode = caenMode
+	m.textInput.Blur()
+	m.resizeCAEN()
+
+	if m.caenVersions == nil {
+		m.loadingCAEN = true
+		return fetchCAENVersions(m.pool, m.dbClient)
+	}
+	return m.loadCAENLevel()
+}
+
+func (m *Model) closeCAEN() {
+	m.mode = searchMode
+	m.textInput.Focus()
+}
+
+// setCAENVersions starts browsing the newest version
+func (m *Model) setCAENVersions(msg caenVersionsMsg) tea.Cmd {
+	if msg.err != nil {
+		m.loadingCAEN = false
+		m.caenErr = msg.err
+		return nil
+	}
+	m.caenVersions = msg.versions
+	m.caenVersion = max(len(msg.versions)-1, 0)
+	m.caenPath = nil
+	return m.loadCAENLevel()
+}
+
+// loadCAENLevel fetches the children of the last node in the path, or the sections
+func (m *Model) loadCAENLevel() tea.Cmd {
+	if len(m.caenVersions) == 0 {
+		m.loadingCAEN = false
+		m.caenErr = fmt.Errorf("no CAEN versions imported yet")
+		return nil
+	}
+
+	level := db.CAENLevels[len(m.caenPath)]
+	var parent string
+	if len(m.caenPath) > 0 {
+		parent = m.caenPath[len(m.caenPath)-1].Code
+	}
+
+	m.caenSeq++
+	m.loadingCAEN = true
+	m.caenErr = nil
+	return fetchCAENNodes(m.pool, m.dbClient, m.caenVersions[m.caenVersion].Code, level, parent, m.caenSeq)
+}
+
+func (m *Model) setCAENNodes(msg caenNodesMsg) {
+	if msg.seq != m.caenSeq {
+		return
+	}
+
+	m.loadingCAEN = false
+	m.caenErr = msg.err
+	m.caenNodes = msg.nodes
+
+	nameWidth := m.caenNameWidth()
+	rows := make([]table.Row, len(msg.nodes))
+	for i, n := range msg.nodes {
+		rows[i] = table.Row{n.Code, truncate(n.Name, nameWidth), humanize.Comma(n.Companies)}
+	}
+	m.caenTable.SetRows(nil)
+	m.caenTable.SetColumns([]table.Column{
+		{Title: "Code", Width: 8},
+		{Title: "Name", Width: nameWidth},
+		{Title: "Companies", Width: 12},
+	})
+	m.caenTable.SetRows(rows)
+	m.caenTable.GotoTop()
+}
+
+// descendCAEN opens the selected node, or lists its companies when it is a class
+func (m *Model) descendCAEN() tea.Cmd {
+	if m.caenTable.Cursor() >= len(m.caenNodes) {
+		return nil
+	}
+	node := m.caenNodes[m.caenTable.Cursor()]
+	if node.Level == "class" {
+		return m.searchCAEN(node)
+	}
+	m.caenPath = append(m.caenPath, node)
+	return m.loadCAENLevel()
+}
+
+// ascendCAEN goes back up one level, reporting false at the sections
+func (m *Model) ascendCAEN() (tea.Cmd, bool) {
+	if len(m.caenPath) == 0 {
+		return nil, false
+	}
+	m.caenPath = m.caenPath[:len(m.caenPath)-1]
+	return m.loadCAENLevel(), true
+}
+
+// switchCAENVersion moves to the next CAEN version and back to its sections
+func (m *Model) switchCAENVersion() tea.Cmd {
+	if len(m.caenVersions) < 2 {
+		return nil
+	}
+	m.caenVersion = (m.caenVersion + 1) % len(m.caenVersions)
+	m.caenPath = nil
+	return m.loadCAENLevel()
+}
+
+// searchCAEN lists the companies authorized for a division, group or class
+func (m *Model) searchCAEN(node goovern.CAENNode) tea.Cmd {
+	if node.Level == "section" {
+		m.caenErr = fmt.Errorf("sections cannot be searched, open a division instead")
+		return nil
+	}
+
+	if m.target == personTarget {
+		m.toggleTarget()
+	}
+	m.mode = searchMode
+	m.textInput.SetValue("caen:" + node.Code)
+	m.textInput.Focus()
+
+	sq := db.SearchQuery{
+		Mode:  db.SearchFuzzy,
+		Limit: searchPageSize,
+		// Only this version's companies, so the list matches the count shown next to the node
+		SearchFilters: db.SearchFilters{CAEN: node.Code, CAENVersion: m.caenVersions[m.caenVersion].Code},
+	}
+	m.searchSeq++
+	m.query = sq
+	m.searching = true
+	m.err = nil
+	m.warning = ""
+	return performSearch(m.pool, m.dbClient, sq, m.searchSeq, false)
+}
+
+func (m Model) caenNameWidth() int {
+	return max(min(m.width-40, 80), 30)
+}
+
+func (m *Model) resizeCAEN() {
+	m.caenTable.SetHeight(max(m.height-caenChrome, 3))
+}
+
+// caenBreadcrumb shows the version and the path to the current level
+func (m Model) caenBreadcrumb() string {
+	if len(m.caenVersions) == 0 {
+		return ""
+	}
+	v := m.caenVersions[m.caenVersion]
+	crumbs := []string{fmt.Sprintf("Version %d", v.Code)}
+	if v.Description != "" {
+		crumbs[0] = v.Description
+	}
+	for _, n := range m.caenPath {
+		crumbs = append(crumbs, n.Code)
+	}
+	return strings.Join(crumbs, " › ")
+}
+
+func (m Model) renderCAEN() string {
+	var content strings.Builder
+
+	title := m.titleStyle.Render("  CAEN Classification  ")
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.labelStyle.Render(m.caenBreadcrumb())))
+	content.WriteString("\n\n")
+
+	tableView := m.borderStyle.Render(m.caenTable.View())
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, tableView))
+	content.WriteString("\n")
+
+	var status string
+	switch {
+	case m.caenErr != nil:
+		status = m.errorStyle.Render("Error: " + m.caenErr.Error())
+	case m.loadingCAEN:
+		status = m.helpStyle.Render("Loading...")
+	case len(m.caenPath) > 0:
+		parent := m.caenPath[len(m.caenPath)-1]
+		status = m.helpStyle.Render(fmt.Sprintf("%s %s: %s", parent.Level, parent.Code, parent.Name))
+	}
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, status))
+	content.WriteString("\n")
+
+	help := m.helpStyle.Render("↑/↓: navigate • enter: open / list companies • f: list companies • ←: up • v: switch version • esc: back • ctrl+c: quit")
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, help))
+
+	return content.String()
+}
+
+func fetchCAENVersions(pool *pgxpool.Pool, dbClient *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		versions, err := dbClient.CAENVersions(context.Background(), pool)
+		return caenVersionsMsg{versions: versions, err: err}
+	}
+}
+
+func fetchCAENNodes(pool *pgxpool.Pool, dbClient *db.DB, version int, level, parent string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		nodes, err := dbClient.CAENChildren(context.Background(), pool, version, level, parent)
+		return caenNodesMsg{nodes: nodes, err: err, seq: seq}
+	}
+}
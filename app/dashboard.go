@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+const (
+	panelWidth     = 62
+	maxLabelWidth  = 24
+	maxLegalForms  = 12
+	maxRecentYears = 35
+	maxImportRuns  = 10
+)
+
+// statsMsg carries the registry statistics shown on the dashboard
+type statsMsg struct {
+	stats   goovern.RegistryStats
+	imports []goovern.ImportRun
+	err     error
+}
+
+func (m *Model) openDashboard() tea.Cmd {
+	m.mode = dashboardMode
+	m.loadingStats = true
+	m.statsErr = nil
+	m.textInput.Blur()
+	m.resizeDashboard()
+	m.refreshDashboard()
+	return fetchStats(m.pool, m.dbClient)
+}
+
+func (m *Model) closeDashboard() {
+	m.mode = searchMode
+	m.textInput.Focus()
+}
+
+func (m *Model) setStats(msg statsMsg) {
+	m.loadingStats = false
+	m.statsErr = msg.err
+	if msg.err == nil {
+		m.stats = &msg.stats
+		m.recentImports = msg.imports
+	}
+	m.refreshDashboard()
+}
+
+// resizeDashboard fits the dashboard viewport to the terminal, leaving room for the title and help
+func (m *Model) resizeDashboard() {
+	width := max(m.width-4, panelWidth)
+	height := max(m.height-6, 3)
+
+	if m.dashboard.Width == 0 {
+		m.dashboard = viewport.New(width, height)
+		return
+	}
+	m.dashboard.Width = width
+	m.dashboard.Height = height
+}
+
+func (m *Model) refreshDashboard() {
+	m.dashboard.SetContent(m.renderStats())
+}
+
+func (m Model) renderStats() string {
+	switch {
+	case m.statsErr != nil:
+		return m.errorStyle.Render("Error: " + m.statsErr.Error())
+	case m.stats == nil:
+		return m.helpStyle.Render("Loading statistics...")
+	case m.stats.Companies == 0:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.helpStyle.Render("No statistics yet. They are computed after the first import.")+"\n",
+			m.renderImports(),
+		)
+	}
+
+	s := m.stats
+	legalForms := s.ByLegalForm[:min(len(s.ByLegalForm), maxLegalForms)]
+	years := s.ByRegistrationYear[max(len(s.ByRegistrationYear)-maxRecentYears, 0):]
+
+	left := []string{
+		m.renderSummary(s),
+		m.renderBars("Companies per county", s.ByCounty),
+		m.renderImports(),
+	}
+	right := []string{
+		m.renderBars("Companies per legal form", legalForms),
+		m.renderBars(fmt.Sprintf("Companies per CAEN section (version %d)", s.CAENVersion), s.ByCAENSection),
+		m.renderBars("Registrations per year", years),
+	}
+
+	if m.dashboard.Width < 2*panelWidth+2 {
+		return lipgloss.JoinVertical(lipgloss.Left, append(left, right...)...)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Left, left...),
+		"  ",
+		lipgloss.JoinVertical(lipgloss.Left, right...),
+	)
+}
+
+// renderSummary shows the totals and a bar split into active, radiated and other companies
+func (m Model) renderSummary(s *goovern.RegistryStats) string {
+	var b strings.Builder
+	b.WriteString(m.labelStyle.Render("Registry") + "\n")
+
+	share := func(n int64) string {
+		return fmt.Sprintf("%s (%.1f%%)", humanize.Comma(n), 100*float64(n)/float64(s.Companies))
+	}
+	fmt.Fprintf(&b, "  %-10s %s\n", "Companies", humanize.Comma(s.Companies))
+	fmt.Fprintf(&b, "  %-10s %s\n", "Active", m.activeBarStyle.Render(share(s.Active)))
+	fmt.Fprintf(&b, "  %-10s %s\n", "Radiated", m.errorStyle.Render(share(s.Radiated)))
+	fmt.Fprintf(&b, "  %-10s %s\n", "Other", m.helpStyle.Render(share(s.Other)))
+
+	width := panelWidth - 2
+	active := int(int64(width) * s.Active / s.Companies)
+	radiated := int(int64(width) * s.Radiated / s.Companies)
+	other := max(width-active-radiated, 0)
+	b.WriteString("  " +
+		m.activeBarStyle.Render(strings.Repeat("█", active)) +
+		m.errorStyle.Render(strings.Repeat("█", radiated)) +
+		m.helpStyle.Render(strings.Repeat("█", other)) + "\n")
+
+	return b.String()
+}
+
+// renderBars draws a horizontal bar chart scaled to the largest count
+func (m Model) renderBars(title string, counts []goovern.StatCount) string {
+	var b strings.Builder
+	b.WriteString(m.labelStyle.Render(title) + "\n")
+	if len(counts) == 0 {
+		b.WriteString(m.helpStyle.Render("  No data") + "\n")
+		return b.String()
+	}
+
+	var largest int64
+	labelWidth := 0
+	for _, c := range counts {
+		largest = max(largest, c.Companies)
+		labelWidth = max(labelWidth, lipgloss.Width(c.Label))
+	}
+	labelWidth = min(labelWidth, maxLabelWidth)
+	valueWidth := len(humanize.Comma(largest))
+	barWidth := panelWidth - labelWidth - valueWidth - 4
+
+	for _, c := range counts {
+		n := int(int64(barWidth) * c.Companies / max(largest, 1))
+		if n == 0 && c.Companies > 0 {
+			n = 1
+		}
+		fmt.Fprintf(&b, "  %s %s %*s\n",
+			padLabel(c.Label, labelWidth),
+			m.barStyle.Render(strings.Repeat("█", n)+strings.Repeat(" ", barWidth-n)),
+			valueWidth, humanize.Comma(c.Companies))
+	}
+	return b.String()
+}
+
+// renderImports lists the latest import runs with their outcome
+func (m Model) renderImports() string {
+	var b strings.Builder
+	b.WriteString(m.labelStyle.Render("Recent imports") + "\n")
+	if len(m.recentImports) == 0 {
+		b.WriteString(m.helpStyle.Render("  No imports yet") + "\n")
+		return b.String()
+	}
+
+	for _, r := range m.recentImports {
+		status := m.helpStyle.Render(fmt.Sprintf("%-9s", r.Status))
+		switch r.Status {
+		case "succeeded":
+			status = m.activeBarStyle.Render(fmt.Sprintf("%-9s", r.Status))
+		case "failed":
+			status = m.errorStyle.Render(fmt.Sprintf("%-9s", r.Status))
+		}
+		when := "-"
+		if r.FinishedAt != nil {
+			when = r.FinishedAt.Local().Format("02 Jan 15:04")
+		}
+		fmt.Fprintf(&b, "  %s %s %-12s %9s",
+			padLabel(r.ResourceName, 16), status, when, "+"+humanize.Comma(r.RowsInserted))
+		if r.RowsRejected > 0 {
+			b.WriteString(m.warningStyle.Render(fmt.Sprintf(" %s rej.", humanize.Comma(r.RowsRejected))))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// padLabel truncates or pads a label to exactly width cells; county names carry diacritics
+func padLabel(label string, width int) string {
+	runes := []rune(label)
+	if len(runes) > width {
+		runes = append(runes[:width-1], '…')
+	}
+	label = string(runes)
+	return label + strings.Repeat(" ", max(width-lipgloss.Width(label), 0))
+}
+
+func fetchStats(pool *pgxpool.Pool, dbClient *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		stats, err := dbClient.RegistryStats(ctx, pool)
+		if err != nil {
+			return statsMsg{err: err}
+		}
+		imports, err := dbClient.ImportRuns(ctx, pool, "", maxImportRuns)
+		return statsMsg{stats: stats, imports: imports, err: err}
+	}
+}
@@ -0,0 +1,374 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// detailTab is a section of the company detail screen
+type detailTab int
+
+const (
+	overviewTab detailTab = iota
+	activitiesTab
+	statusTab
+	legalTab
+	familyTab
+	branchesTab
+	relatedTab
+)
+
+var detailTabNames = []string{
+	"Overview",
+	"Activities",
+	"Status",
+	"Legal",
+	"Family",
+	"Branches",
+	"Related",
+}
+
+const (
+	maxDetailWidth = 100
+	// detailChrome is the height of everything around the viewport: tabs, title, borders, padding and help
+	detailChrome = 12
+)
+
+// profileMsg carries the full profile of the company opened in the detail screen
+type profileMsg struct {
+	profile          goovern.CompanyProfile
+	registrationCode string
+	found            bool
+	err              error
+}
+
+// openDetail switches to the detail screen and loads the full profile of a company.
+// A known company row is shown on the overview while the rest loads.
+func (m *Model) openDetail(registrationCode string, company *goovern.Company) tea.Cmd {
+	m.detailHistory = nil
+	return m.showDetail(registrationCode, company)
+}
+
+func (m *Model) showDetail(registrationCode string, company *goovern.Company) tea.Cmd {
+	m.mode = detailMode
+	m.detailTab = overviewTab
+	m.detailCode = registrationCode
+	m.loadingProfile = true
+	m.profileErr = nil
+	m.profile = nil
+	if company != nil {
+		m.profile = &goovern.CompanyProfile{Company: *company}
+	}
+
+	m.related = nil
+	m.relatedErr = nil
+	m.loadingRelated = true
+	m.relatedTable.SetRows(nil)
+
+	m.resizeDetail()
+	m.refreshDetail()
+	return tea.Batch(
+		fetchProfile(m.pool, m.dbClient, registrationCode),
+		fetchRelated(m.pool, m.dbClient, registrationCode),
+	)
+}
+
+// setProfile shows a loaded profile if it belongs to the company still on screen
+func (m *Model) setProfile(msg profileMsg) {
+	if m.mode != detailMode || msg.registrationCode != m.detailCode {
+		return
+	}
+
+	m.loadingProfile = false
+	switch {
+	case msg.err != nil:
+		m.profileErr = msg.err
+	case !msg.found:
+		m.profileErr = errors.New("company not found")
+	default:
+		var rank float32
+		if m.profile != nil {
+			rank = m.profile.Rank
+		}
+		m.profile = &msg.profile
+		m.profile.Rank = rank
+	}
+	m.refreshDetail()
+}
+
+// closeDetail leaves the detail screen for the search results
+func (m *Model) closeDetail() {
+	m.mode = resultsMode
+	m.profile = nil
+	m.related = nil
+	m.detailCode = ""
+	m.detailHistory = nil
+}
+
+// switchTab moves to another tab, wrapping around at both ends
+func (m *Model) switchTab(delta int) {
+	n := len(detailTabNames)
+	m.detailTab = detailTab((int(m.detailTab) + delta + n) % n)
+	m.refreshDetail()
+}
+
+func (m Model) detailWidth() int {
+	return max(min(m.width-6, maxDetailWidth), 40)
+}
+
+// resizeDetail fits the viewport to the terminal
+func (m *Model) resizeDetail() {
+	// The detail box adds a border and two columns of padding on each side
+	width := m.detailWidth() - 6
+	height := max(m.height-detailChrome, 3)
+
+	if m.viewport.Width == 0 {
+		m.viewport = viewport.New(width, height)
+	}
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.relatedTable.SetWidth(width)
+	m.relatedTable.SetHeight(height)
+}
+
+// refreshDetail renders the active tab into the viewport and scrolls back to the top
+func (m *Model) refreshDetail() {
+	m.viewport.SetContent(m.renderTab())
+	m.viewport.GotoTop()
+}
+
+func (m Model) renderTab() string {
+	if m.profileErr != nil {
+		return m.errorStyle.Render("Error: " + m.profileErr.Error())
+	}
+	if m.profile == nil || (m.loadingProfile && m.detailTab != overviewTab) {
+		return m.helpStyle.Render("Loading profile...")
+	}
+
+	p := m.profile
+	wrap := lipgloss.NewStyle().Width(m.viewport.Width)
+
+	switch m.detailTab {
+	case activitiesTab:
+		if len(p.Activities) == 0 {
+			return m.helpStyle.Render("No authorized activities recorded.")
+		}
+		var b strings.Builder
+		version := 0
+		for _, a := range p.Activities {
+			if a.CAENVersion != version {
+				version = a.CAENVersion
+				if b.Len() > 0 {
+					b.WriteString("\n")
+				}
+				b.WriteString(m.labelStyle.Render(fmt.Sprintf("CAEN version %d", version)) + "\n")
+			}
+			b.WriteString(wrap.Render(fmt.Sprintf("  %-6s %s", a.CAENCode, a.CAENName)) + "\n")
+		}
+		return b.String()
+
+	case statusTab:
+		if len(p.StatusHistory) == 0 {
+			return m.helpStyle.Render("No status recorded.")
+		}
+		var b strings.Builder
+		for _, s := range p.StatusHistory {
+			b.WriteString(wrap.Render(fmt.Sprintf("  %-4d %s", s.Code, s.Name)) + "\n")
+		}
+		return b.String()
+
+	case legalTab:
+		if len(p.LegalRepresentatives) == 0 {
+			return m.helpStyle.Render("No legal representatives recorded.")
+		}
+		var b strings.Builder
+		for _, r := range p.LegalRepresentatives {
+			b.WriteString(m.labelStyle.Render(r.AuthorizedPerson) + "\n")
+			writeDetailLine(&b, wrap, "Role", r.Role)
+			writeDetailLine(&b, wrap, "Born", joinNonEmpty(r.BirthDate, r.BirthLocality, r.BirthCounty, r.BirthCountry))
+			writeDetailLine(&b, wrap, "Residence", joinNonEmpty(r.Locality, r.County, r.Country))
+			b.WriteString("\n")
+		}
+		return b.String()
+
+	case familyTab:
+		if len(p.FamilyBusinessRepresentatives) == 0 {
+			return m.helpStyle.Render("No family business members recorded.")
+		}
+		var b strings.Builder
+		for _, r := range p.FamilyBusinessRepresentatives {
+			b.WriteString(m.labelStyle.Render(r.Name) + "\n")
+			writeDetailLine(&b, wrap, "Role", r.Role)
+			writeDetailLine(&b, wrap, "Born", joinNonEmpty(r.BirthDate, r.BirthLocality, r.BirthCounty, r.BirthCountry))
+			b.WriteString("\n")
+		}
+		return b.String()
+
+	case branchesTab:
+		if len(p.ForeignBranches) == 0 {
+			return m.helpStyle.Render("No foreign branches recorded.")
+		}
+		var b strings.Builder
+		for _, br := range p.ForeignBranches {
+			b.WriteString(m.labelStyle.Render(br.BranchName) + "\n")
+			writeDetailLine(&b, wrap, "Type", br.UnitType)
+			writeDetailLine(&b, wrap, "Country", br.Country)
+			writeDetailLine(&b, wrap, "EUID", br.EUID)
+			writeDetailLine(&b, wrap, "Tax code", br.TaxCode)
+			b.WriteString("\n")
+		}
+		return b.String()
+
+	default:
+		return m.renderOverview(wrap)
+	}
+}
+
+func (m Model) renderOverview(wrap lipgloss.Style) string {
+	c := m.profile.Company
+	var content strings.Builder
+
+	renderField := func(label, value string) {
+		if value != "" {
+			content.WriteString(m.labelStyle.Render(label) + "\n")
+			content.WriteString(wrap.Render("  "+value) + "\n\n")
+		}
+	}
+
+	renderField("Company Name", c.Name)
+	renderField("Tax ID (CUI)", c.TaxID)
+	renderField("Registration Code", c.RegistrationCode)
+	renderField("Registration Date", c.RegistrationDate)
+	renderField("Legal Form", c.LegalForm)
+	renderField("EUID", c.EUID)
+
+	var location []string
+	if c.Locality != "" {
+		location = append(location, c.Locality)
+	}
+	if c.County != "" {
+		location = append(location, c.County)
+	}
+	if c.Country != "" {
+		location = append(location, c.Country)
+	}
+	if c.Sector != "" {
+		location = append(location, "Sector "+c.Sector)
+	}
+	renderField("Location", strings.Join(location, ", "))
+
+	var address []string
+	if c.StreetName != "" {
+		streetAddr := c.StreetName
+		if c.StreetNumber != "" {
+			streetAddr += " " + c.StreetNumber
+		}
+		address = append(address, streetAddr)
+	}
+	if c.Building != "" {
+		address = append(address, "Building "+c.Building)
+	}
+	if c.Staircase != "" {
+		address = append(address, "Staircase "+c.Staircase)
+	}
+	if c.Floor != "" {
+		address = append(address, "Floor "+c.Floor)
+	}
+	if c.Apartment != "" {
+		address = append(address, "Apt "+c.Apartment)
+	}
+	if c.PostalCode != "" {
+		address = append(address, "Postal Code: "+c.PostalCode)
+	}
+	renderField("Address", strings.Join(address, ", "))
+
+	renderField("Address Details", c.AddressDetails)
+	renderField("Website", c.Website)
+	renderField("Parent Company Country", c.ParentCompanyCountry)
+
+	if !m.loadingProfile {
+		var statuses []string
+		for _, s := range m.profile.StatusHistory {
+			statuses = append(statuses, s.Name)
+		}
+		renderField("Status", strings.Join(statuses, ", "))
+	}
+
+	if c.Rank != 0 {
+		renderField("Search Rank Score", fmt.Sprintf("%.6f", c.Rank))
+	}
+
+	return content.String()
+}
+
+// renderTabs renders the tab bar with a count of records next to each list tab
+func (m Model) renderTabs() string {
+	tabs := make([]string, len(detailTabNames))
+	for i, name := range detailTabNames {
+		if n, ok := m.tabCount(detailTab(i)); ok {
+			name = fmt.Sprintf("%s (%d)", name, n)
+		}
+		style := m.tabStyle
+		if detailTab(i) == m.detailTab {
+			style = m.activeTabStyle
+		}
+		tabs[i] = style.Render(name)
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (m Model) tabCount(tab detailTab) (int, bool) {
+	if tab == relatedTab {
+		return len(m.related), !m.loadingRelated && m.relatedErr == nil
+	}
+	if m.profile == nil || m.loadingProfile || m.profileErr != nil {
+		return 0, false
+	}
+	switch tab {
+	case activitiesTab:
+		return len(m.profile.Activities), true
+	case statusTab:
+		return len(m.profile.StatusHistory), true
+	case legalTab:
+		return len(m.profile.LegalRepresentatives), true
+	case familyTab:
+		return len(m.profile.FamilyBusinessRepresentatives), true
+	case branchesTab:
+		return len(m.profile.ForeignBranches), true
+	default:
+		return 0, false
+	}
+}
+
+func writeDetailLine(b *strings.Builder, wrap lipgloss.Style, label, value string) {
+	if value != "" {
+		b.WriteString(wrap.Render("  "+label+": "+value) + "\n")
+	}
+}
+
+func joinNonEmpty(values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fetchProfile(pool *pgxpool.Pool, dbClient *db.DB, registrationCode string) tea.Cmd {
+	return func() tea.Msg {
+		profile, found, err := dbClient.CompanyProfile(context.Background(), pool, registrationCode)
+		return profileMsg{profile: profile, registrationCode: registrationCode, found: found, err: err}
+	}
+}
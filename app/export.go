@@ -0,0 +1,156 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/export"
+)
+
+const (
+	// maxExportRows caps a full result set export, which is held in memory until fetched
+	maxExportRows  = 10000
+	exportPageSize = 100
+)
+
+// clipboardMsg reports a copy to the client's clipboard
+type clipboardMsg struct {
+	what string
+	err  error
+}
+
+// exportMsg reports a result set saved for download over SCP/SFTP
+type exportMsg struct {
+	name  string
+	count int
+	err   error
+}
+
+// copySelected copies the selected company, profile or representation to the client's
+// clipboard over OSC 52, as JSON or as CSV with a header row
+func (m Model) copySelected(asCSV bool) tea.Cmd {
+	var (
+		what string
+		buf  bytes.Buffer
+		err  error
+	)
+
+	switch {
+	case m.mode == detailMode && m.profile != nil:
+		what = m.profile.Name
+		if asCSV {
+			err = export.WriteCSV(&buf, export.CompanyHeader, []goovern.Company{m.profile.Company}, export.CompanyRecord)
+		} else {
+			err = export.WriteJSON(&buf, m.profile)
+		}
+	case m.mode == resultsMode && m.target == personTarget && m.table.Cursor() < len(m.people):
+		p := m.people[m.table.Cursor()]
+		what = p.Person
+		if asCSV {
+			err = export.WriteCSV(&buf, export.RepresentationHeader, []goovern.Representation{p}, export.RepresentationRecord)
+		} else {
+			err = export.WriteJSON(&buf, p)
+		}
+	case m.mode == resultsMode && m.target == companyTarget && m.table.Cursor() < len(m.results):
+		c := m.results[m.table.Cursor()]
+		what = c.Name
+		if asCSV {
+			err = export.WriteCSV(&buf, export.CompanyHeader, []goovern.Company{c}, export.CompanyRecord)
+		} else {
+			err = export.WriteJSON(&buf, c)
+		}
+	default:
+		return nil
+	}
+
+	clipboard := m.clipboard
+	return func() tea.Msg {
+		if err == nil {
+			clipboard.Copy(buf.String())
+		}
+		return clipboardMsg{what: what, err: err}
+	}
+}
+
+// exportResults saves the whole result set, not only the loaded pages, for download over SCP/SFTP
+func (m Model) exportResults(asJSON bool) tea.Cmd {
+	if m.exports == nil {
+		return func() tea.Msg { return exportMsg{err: errors.New("exports are not available")} }
+	}
+
+	ext := "csv"
+	if asJSON {
+		ext = "json"
+	}
+	name := fmt.Sprintf("goovern-%s.%s", time.Now().Format("20060102-150405"), ext)
+
+	exports, user := m.exports, m.user
+	people, target, query := m.people, m.target, m.query
+	pool, dbClient := m.pool, m.dbClient
+
+	return func() tea.Msg {
+		var (
+			buf   bytes.Buffer
+			count int
+			err   error
+		)
+
+		if target == personTarget {
+			count = len(people)
+			if asJSON {
+				err = export.WriteJSON(&buf, people)
+			} else {
+				err = export.WriteCSV(&buf, export.RepresentationHeader, people, export.RepresentationRecord)
+			}
+		} else {
+			var results []goovern.Company
+			if results, err = searchAll(context.Background(), dbClient, pool, query); err == nil {
+				count = len(results)
+				if asJSON {
+					err = export.WriteJSON(&buf, results)
+				} else {
+					err = export.WriteCSV(&buf, export.CompanyHeader, results, export.CompanyRecord)
+				}
+			}
+		}
+		if err != nil {
+			return exportMsg{err: err}
+		}
+
+		exports.Put(user, name, buf.Bytes())
+		return exportMsg{name: name, count: count}
+	}
+}
+
+// searchAll pages through every result of a search, up to maxExportRows
+func searchAll(ctx context.Context, dbClient *db.DB, pool db.Querier, sq db.SearchQuery) ([]goovern.Company, error) {
+	sq.Limit = exportPageSize
+	sq.After = nil
+
+	var all []goovern.Company
+	for len(all) < maxExportRows {
+		results, next, err := dbClient.Search(ctx, pool, sq)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results...)
+		if next == nil {
+			break
+		}
+		sq.After = next
+	}
+	return all[:min(len(all), maxExportRows)], nil
+}
+
+// exportNotice tells the user how to fetch a saved export
+func (m Model) exportNotice(msg exportMsg) string {
+	return fmt.Sprintf("Saved %d rows to %s for an hour • fetch with: scp -P %d %s@<host>:%s .",
+		msg.count, msg.name, m.sshPort, m.user, msg.name)
+}
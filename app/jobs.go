@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// jobsRefreshInterval is how often the jobs screen polls while it is open
+const jobsRefreshInterval = time.Second
+
+// jobsMsg carries the running jobs; seq identifies the screen opening that asked for them
+type jobsMsg struct {
+	jobs []goovern.JobProgress
+	err  error
+	seq  int
+}
+
+type jobsTickMsg struct {
+	seq int
+}
+
+// openJobs shows the running jobs and starts polling them
+func (m *Model) openJobs() tea.Cmd {
+	m.mode = jobsMode
+	m.textInput.Blur()
+	m.jobsSeq++
+	m.loadingJobs = true
+	m.jobsErr = nil
+	return fetchJobs(m.pool, m.dbClient, m.jobsSeq)
+}
+
+func (m *Model) closeJobs() {
+	m.mode = searchMode
+	m.textInput.Focus()
+}
+
+// setJobs stores a poll result and schedules the next one while the screen stays open
+func (m *Model) setJobs(msg jobsMsg) tea.Cmd {
+	if msg.seq != m.jobsSeq || m.mode != jobsMode {
+		return nil
+	}
+	m.loadingJobs = false
+	m.jobsErr = msg.err
+	if msg.err == nil {
+		m.jobs = msg.jobs
+	}
+	seq := msg.seq
+	return tea.Tick(jobsRefreshInterval, func(time.Time) tea.Msg {
+		return jobsTickMsg{seq: seq}
+	})
+}
+
+func (m Model) renderJobs() string {
+	var content strings.Builder
+
+	title := m.titleStyle.Render("  Running Jobs  ")
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
+	content.WriteString("\n\n")
+
+	var body string
+	switch {
+	case m.jobsErr != nil:
+		body = m.errorStyle.Render("Error: " + m.jobsErr.Error())
+	case m.loadingJobs && m.jobs == nil:
+		body = m.helpStyle.Render("Loading jobs...")
+	case len(m.jobs) == 0:
+		body = m.helpStyle.Render("No jobs are running.")
+	default:
+		blocks := make([]string, len(m.jobs))
+		for i, job := range m.jobs {
+			blocks[i] = m.renderJob(job)
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, blocks...)
+	}
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.detailStyle.Width(panelWidth+4).Render(body)))
+	content.WriteString("\n\n")
+
+	help := m.helpStyle.Render(fmt.Sprintf("refreshes every %s • esc: back to search • ctrl+c: quit", jobsRefreshInterval))
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, help))
+
+	return content.String()
+}
+
+// renderJob shows one job with a progress bar when its size is known
+func (m Model) renderJob(job goovern.JobProgress) string {
+	var b strings.Builder
+
+	name := job.ResourceName
+	if name == "" {
+		name = "-"
+	}
+	running := time.Since(job.StartedAt).Round(time.Second)
+	fmt.Fprintf(&b, "%s %s\n", m.labelStyle.Render(fmt.Sprintf("%-9s", job.Kind)), name)
+
+	if job.UpdatedAt == nil {
+		fmt.Fprintf(&b, "%s\n", m.helpStyle.Render(fmt.Sprintf("attempt %d • running %s • no progress reported", job.Attempt, running)))
+		return b.String()
+	}
+
+	if job.TotalBytes > 0 {
+		percent := min(float64(job.Bytes)/float64(job.TotalBytes), 1)
+		b.WriteString(m.jobProgress.ViewAs(percent) + "\n")
+	}
+
+	var details []string
+	if job.Rows > 0 {
+		details = append(details, humanize.Comma(job.Rows)+" rows")
+	}
+	size := humanize.Bytes(uint64(job.Bytes))
+	if job.TotalBytes > 0 {
+		size += " / " + humanize.Bytes(uint64(job.TotalBytes))
+	}
+	details = append(details, size, "running "+running.String())
+	if job.ETASeconds != nil {
+		eta := time.Duration(*job.ETASeconds * float64(time.Second)).Round(time.Second)
+		details = append(details, "ETA "+eta.String())
+	}
+	if job.Attempt > 1 {
+		details = append(details, fmt.Sprintf("attempt %d", job.Attempt))
+	}
+	b.WriteString(m.helpStyle.Render(strings.Join(details, " • ")) + "\n")
+
+	return b.String()
+}
+
+func fetchJobs(pool *pgxpool.Pool, dbClient *db.DB, seq int) tea.Cmd {
+	return func() tea.Msg {
+		jobs, err := dbClient.RunningJobs(context.Background(), pool)
+		return jobsMsg{jobs: jobs, err: err, seq: seq}
+	}
+}
@@ -1,14 +1,18 @@
 package app
 
 import (
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/muesli/termenv"
 
 	"github.com/ionut-maxim/goovern"
 	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/export"
 )
 
 type viewMode int
@@ -16,26 +20,84 @@ type viewMode int
 const (
 	searchMode viewMode = iota
 	resultsMode
-	modalMode
+	detailMode
+	dashboardMode
+	caenMode
+	jobsMode
+)
+
+// searchTarget selects what the search input looks for
+type searchTarget int
+
+const (
+	companyTarget searchTarget = iota
+	personTarget
 )
 
 type Model struct {
 	textInput        textinput.Model
 	pool             *pgxpool.Pool
 	dbClient         *db.DB
+	exports          *export.Store
+	clipboard        *termenv.Output
+	user             string
+	sshPort          int
+	notice           string
+	target           searchTarget
 	results          []goovern.Company
+	people           []goovern.Representation
 	searching        bool
+	searchSeq        int
+	liveResults      bool
+	query            db.SearchQuery
+	nextCursor       *db.SearchCursor
+	total            int
+	loadingMore      bool
 	err              error
+	warning          string
 	table            table.Model
 	mode             viewMode
-	selectedCompany  *goovern.Company
+	profile          *goovern.CompanyProfile
+	detailCode       string
+	detailTab        detailTab
+	loadingProfile   bool
+	profileErr       error
+	viewport         viewport.Model
+	related          []goovern.RelatedCompany
+	relatedErr       error
+	loadingRelated   bool
+	relatedTable     table.Model
+	detailHistory    []string
+	stats            *goovern.RegistryStats
+	recentImports    []goovern.ImportRun
+	statsErr         error
+	loadingStats     bool
+	dashboard        viewport.Model
+	caenVersions     []goovern.CAENVersion
+	caenVersion      int
+	caenPath         []goovern.CAENNode
+	caenNodes        []goovern.CAENNode
+	caenErr          error
+	loadingCAEN      bool
+	caenSeq          int
+	caenTable        table.Model
+	jobs             []goovern.JobProgress
+	jobsErr          error
+	loadingJobs      bool
+	jobsSeq          int
+	jobProgress      progress.Model
 	titleStyle       lipgloss.Style
 	borderStyle      lipgloss.Style
 	inputBorderStyle lipgloss.Style
 	helpStyle        lipgloss.Style
 	errorStyle       lipgloss.Style
-	modalStyle       lipgloss.Style
+	warningStyle     lipgloss.Style
+	detailStyle      lipgloss.Style
+	tabStyle         lipgloss.Style
+	activeTabStyle   lipgloss.Style
 	labelStyle       lipgloss.Style
+	barStyle         lipgloss.Style
+	activeBarStyle   lipgloss.Style
 	width            int
 	height           int
 }
@@ -46,5 +108,21 @@ func (m Model) Init() tea.Cmd {
 
 type searchResultMsg struct {
 	results []goovern.Company
+	next    *db.SearchCursor
+	total   int
 	err     error
+	seq     int
+	live    bool
+	more    bool // results continue the current page instead of replacing it
+}
+
+type peopleResultMsg struct {
+	results []goovern.Representation
+	err     error
+	seq     int
+}
+
+// liveSearchMsg fires once typing has paused; seq identifies the keystroke that scheduled it
+type liveSearchMsg struct {
+	seq int
 }
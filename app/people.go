@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// peopleSearchLimit caps person searches, which return one row per represented company
+const peopleSearchLimit = 100
+
+// toggleTarget switches the search between companies and representatives, clearing stale results
+func (m *Model) toggleTarget() {
+	m.searchSeq++
+	m.searching = false
+	m.err = nil
+	m.warning = ""
+	m.liveResults = false
+	m.nextCursor = nil
+	m.total = 0
+	m.results = nil
+	m.people = nil
+	m.table.SetRows(nil)
+
+	if m.target == companyTarget {
+		m.target = personTarget
+		m.textInput.Placeholder = personPlaceholder
+		m.table.SetColumns(personTableColumns)
+	} else {
+		m.target = companyTarget
+		m.textInput.Placeholder = companyPlaceholder
+		m.table.SetColumns(companyTableColumns)
+	}
+}
+
+// setPeople replaces the current results with representatives and the companies they represent
+func (m *Model) setPeople(people []goovern.Representation) {
+	m.people = people
+
+	rows := make([]table.Row, len(people))
+	for i, p := range people {
+		rows[i] = table.Row{
+			truncate(p.Person, 28),
+			truncate(p.Role, 18),
+			truncate(p.CompanyName, 30),
+			p.TaxID,
+		}
+	}
+	m.table.SetRows(rows)
+	m.table.GotoTop()
+}
+
+func performPeopleSearch(pool *pgxpool.Pool, dbClient *db.DB, searchTerm string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		results, err := dbClient.SearchPeople(context.Background(), pool, searchTerm, peopleSearchLimit)
+		return peopleResultMsg{results: results, err: err, seq: seq}
+	}
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// relatedLimit caps related companies; addresses of registered office providers can link thousands
+const relatedLimit = 200
+
+// relatedMsg carries the companies related to the one opened in the detail screen
+type relatedMsg struct {
+	related          []goovern.RelatedCompany
+	registrationCode string
+	err              error
+}
+
+// setRelated shows loaded related companies if they belong to the company still on screen
+func (m *Model) setRelated(msg relatedMsg) {
+	if m.mode != detailMode || msg.registrationCode != m.detailCode {
+		return
+	}
+
+	m.loadingRelated = false
+	m.relatedErr = msg.err
+	m.related = msg.related
+
+	rows := make([]table.Row, len(msg.related))
+	for i, rc := range msg.related {
+		rows[i] = table.Row{
+			truncate(rc.Name, 30),
+			rc.TaxID,
+			rc.Reason,
+			truncate(rc.Link, 30),
+		}
+	}
+	m.relatedTable.SetRows(rows)
+	m.relatedTable.GotoTop()
+}
+
+// followRelated opens the selected related company, remembering the current one for esc
+func (m *Model) followRelated() tea.Cmd {
+	if m.relatedTable.Cursor() >= len(m.related) {
+		return nil
+	}
+	rc := m.related[m.relatedTable.Cursor()]
+	m.detailHistory = append(m.detailHistory, m.detailCode)
+	return m.showDetail(rc.RegistrationCode, &rc.Company)
+}
+
+// backDetail returns to the previously viewed company, reporting false when there is none
+func (m *Model) backDetail() (tea.Cmd, bool) {
+	if len(m.detailHistory) == 0 {
+		return nil, false
+	}
+	prev := m.detailHistory[len(m.detailHistory)-1]
+	m.detailHistory = m.detailHistory[:len(m.detailHistory)-1]
+	cmd := m.showDetail(prev, nil)
+	m.detailTab = relatedTab
+	return cmd, true
+}
+
+func (m Model) renderRelated() string {
+	switch {
+	case m.relatedErr != nil:
+		return m.errorStyle.Render("Error: " + m.relatedErr.Error())
+	case m.loadingRelated:
+		return m.helpStyle.Render("Looking for related companies...")
+	case len(m.related) == 0:
+		return m.helpStyle.Render("No companies share a representative or the registered address.")
+	default:
+		return m.relatedTable.View()
+	}
+}
+
+func fetchRelated(pool *pgxpool.Pool, dbClient *db.DB, registrationCode string) tea.Cmd {
+	return func() tea.Msg {
+		related, err := dbClient.RelatedCompanies(context.Background(), pool, registrationCode, relatedLimit)
+		return relatedMsg{related: related, registrationCode: registrationCode, err: err}
+	}
+}
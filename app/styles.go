@@ -1,6 +1,9 @@
 package app
 
 import (
+	"net"
+
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,11 +14,43 @@ import (
 	"github.com/muesli/termenv"
 
 	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/export"
+)
+
+const (
+	companyPlaceholder = "Company name or CUI, filters like county:CLUJ caen:6201 status:active"
+	personPlaceholder  = "Representative name..."
+)
+
+var (
+	companyTableColumns = []table.Column{
+		{Title: "Name", Width: 35},
+		{Title: "CUI", Width: 12},
+		{Title: "Reg Code", Width: 12},
+		{Title: "Legal Form", Width: 20},
+	}
+	relatedTableColumns = []table.Column{
+		{Title: "Name", Width: 30},
+		{Title: "CUI", Width: 10},
+		{Title: "Linked by", Width: 14},
+		{Title: "Shared", Width: 30},
+	}
+	personTableColumns = []table.Column{
+		{Title: "Person", Width: 28},
+		{Title: "Role", Width: 18},
+		{Title: "Company", Width: 30},
+		{Title: "CUI", Width: 12},
+	}
 )
 
-func NewModel(s ssh.Session, pool *pgxpool.Pool, dbClient *db.DB) (tea.Model, []tea.ProgramOption) {
+func NewModel(s ssh.Session, pool *pgxpool.Pool, dbClient *db.DB, exports *export.Store) (tea.Model, []tea.ProgramOption) {
 	pty, _, _ := s.Pty()
 
+	var sshPort int
+	if addr, ok := s.LocalAddr().(*net.TCPAddr); ok {
+		sshPort = addr.Port
+	}
+
 	renderer := bubbletea.MakeRenderer(s)
 	lipgloss.SetColorProfile(termenv.TrueColor)
 
@@ -45,32 +80,44 @@ func NewModel(s ssh.Session, pool *pgxpool.Pool, dbClient *db.DB) (tea.Model, []
 		Foreground(lipgloss.Color("#FF0000")).
 		Bold(true)
 
-	modalStyle := renderer.NewStyle().
+	warningStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#FFB86C")).
+		Bold(true)
+
+	detailStyle := renderer.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("#874BFD")).
-		Padding(1, 2).
-		Width(60)
+		Padding(1, 2)
+
+	tabStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Padding(0, 1)
+
+	activeTabStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#7D56F4")).
+		Bold(true).
+		Padding(0, 1)
 
 	labelStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("#7D56F4")).
 		Bold(true)
 
+	barStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	activeBarStyle := renderer.NewStyle().
+		Foreground(lipgloss.Color("#50FA7B"))
+
 	ti := textinput.New()
-	ti.Placeholder = "Search by company name or CUI..."
+	ti.Placeholder = companyPlaceholder
 	ti.Focus()
 	ti.CharLimit = 156
-	ti.Width = 50
+	ti.Width = 70
 
 	// Create table with bubbles styling
-	columns := []table.Column{
-		{Title: "Name", Width: 35},
-		{Title: "CUI", Width: 12},
-		{Title: "Reg Code", Width: 12},
-		{Title: "Legal Form", Width: 20},
-	}
-
 	t := table.New(
-		table.WithColumns(columns),
+		table.WithColumns(companyTableColumns),
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
@@ -89,19 +136,42 @@ func NewModel(s ssh.Session, pool *pgxpool.Pool, dbClient *db.DB) (tea.Model, []
 		Underline(true)
 	t.SetStyles(tableStyles)
 
+	related := table.New(
+		table.WithColumns(relatedTableColumns),
+		table.WithFocused(true),
+	)
+	related.SetStyles(tableStyles)
+
+	caen := table.New(table.WithFocused(true))
+	caen.SetStyles(tableStyles)
+
+	jobProgress := progress.New(progress.WithSolidFill("#7D56F4"), progress.WithWidth(panelWidth))
+
 	m := Model{
 		textInput:        ti,
 		pool:             pool,
 		dbClient:         dbClient,
+		exports:          exports,
+		clipboard:        renderer.Output(),
+		user:             s.User(),
+		sshPort:          sshPort,
 		table:            t,
+		relatedTable:     related,
+		caenTable:        caen,
+		jobProgress:      jobProgress,
 		mode:             searchMode,
 		titleStyle:       titleStyle,
 		borderStyle:      borderStyle,
 		inputBorderStyle: inputBorderStyle,
 		helpStyle:        helpStyle,
 		errorStyle:       errorStyle,
-		modalStyle:       modalStyle,
+		warningStyle:     warningStyle,
+		detailStyle:      detailStyle,
+		tabStyle:         tabStyle,
+		activeTabStyle:   activeTabStyle,
 		labelStyle:       labelStyle,
+		barStyle:         barStyle,
+		activeBarStyle:   activeBarStyle,
 		width:            pty.Window.Width,
 		height:           pty.Window.Height,
 	}
@@ -2,14 +2,29 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/ionut-maxim/goovern"
 	"github.com/ionut-maxim/goovern/db"
 )
 
+const (
+	// liveSearchDelay is how long typing must pause before a live search runs
+	liveSearchDelay = 300 * time.Millisecond
+	// minLiveSearchLength avoids prefix scans on one or two letters, which match most of the registry
+	minLiveSearchLength = 3
+	// searchPageSize is how many results are fetched at once; more load when scrolling past the end
+	searchPageSize = 20
+)
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -17,6 +32,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.mode == detailMode {
+			m.resizeDetail()
+			m.refreshDetail()
+		}
+		if m.mode == dashboardMode {
+			m.resizeDashboard()
+			m.refreshDashboard()
+		}
+		if m.mode == caenMode {
+			m.resizeCAEN()
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -25,15 +51,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyCtrlC, tea.KeyEsc:
 				return m, tea.Quit
+			case tea.KeyTab:
+				m.toggleTarget()
+				return m, nil
+			case tea.KeyCtrlD:
+				return m, m.openDashboard()
+			case tea.KeyCtrlB:
+				return m, m.openCAEN()
+			case tea.KeyCtrlT:
+				return m, m.openJobs()
 			case tea.KeyEnter:
-				searchTerm := m.textInput.Value()
-				if searchTerm != "" {
+				if m.textInput.Value() != "" && m.target == personTarget {
+					m.searchSeq++
 					m.searching = true
 					m.err = nil
-					return m, performSearch(m.pool, m.dbClient, searchTerm)
+					m.warning = ""
+					return m, performPeopleSearch(m.pool, m.dbClient, m.textInput.Value(), m.searchSeq)
+				}
+				if m.textInput.Value() != "" {
+					m.searchSeq++
+					sq, err := db.ParseSearchQuery(m.textInput.Value())
+					if err != nil {
+						m.err = err
+						return m, nil
+					}
+					sq.Mode = db.SearchFuzzy
+					sq.Limit = searchPageSize
+
+					m.query = sq
+					m.searching = true
+					m.err = nil
+					m.warning = cuiWarning(sq.Term)
+					return m, performSearch(m.pool, m.dbClient, sq, m.searchSeq, false)
 				}
 			}
+			prev := m.textInput.Value()
 			m.textInput, cmd = m.textInput.Update(msg)
+			if m.textInput.Value() != prev {
+				m.searchSeq++
+				return m, tea.Batch(cmd, debounceSearch(m.searchSeq))
+			}
 			return m, cmd
 
 		case resultsMode:
@@ -44,42 +101,235 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case tea.KeyEnter:
 				selectedRow := m.table.SelectedRow()
+				if len(selectedRow) > 0 && m.target == personTarget && len(m.people) > m.table.Cursor() {
+					return m, m.openDetail(m.people[m.table.Cursor()].RegistrationCode, nil)
+				}
 				if len(selectedRow) > 0 && len(m.results) > m.table.Cursor() {
-					m.selectedCompany = &m.results[m.table.Cursor()]
-					m.mode = modalMode
-					return m, nil
+					company := m.results[m.table.Cursor()]
+					return m, m.openDetail(company.RegistrationCode, &company)
 				}
 			}
+			switch msg.String() {
+			case "y", "Y":
+				m.notice = ""
+				return m, m.copySelected(msg.String() == "Y")
+			case "e", "E":
+				m.notice = "Exporting results..."
+				return m, m.exportResults(msg.String() == "E")
+			}
+			atEnd := m.table.Cursor() == len(m.results)-1
 			m.table, cmd = m.table.Update(msg)
+
+			// Scrolling past the last row fetches the next page
+			switch msg.String() {
+			case "down", "j", "pgdown":
+				if atEnd && m.nextCursor != nil && !m.loadingMore {
+					m.loadingMore = true
+					sq := m.query
+					sq.After = m.nextCursor
+					return m, tea.Batch(cmd, performSearch(m.pool, m.dbClient, sq, m.searchSeq, false))
+				}
+			}
 			return m, cmd
 
-		case modalMode:
-			switch msg.Type {
-			case tea.KeyCtrlC:
+		case caenMode:
+			switch msg.String() {
+			case "ctrl+c":
 				return m, tea.Quit
-			case tea.KeyEsc, tea.KeyEnter:
-				m.mode = resultsMode
-				m.selectedCompany = nil
+			case "esc":
+				if cmd, ok := m.ascendCAEN(); ok {
+					return m, cmd
+				}
+				m.closeCAEN()
+				return m, nil
+			case "left", "h", "backspace":
+				cmd, _ := m.ascendCAEN()
+				return m, cmd
+			case "enter", "right", "l":
+				return m, m.descendCAEN()
+			case "f":
+				if m.caenTable.Cursor() < len(m.caenNodes) {
+					return m, m.searchCAEN(m.caenNodes[m.caenTable.Cursor()])
+				}
 				return m, nil
+			case "v":
+				return m, m.switchCAENVersion()
 			}
+			m.caenTable, cmd = m.caenTable.Update(msg)
+			return m, cmd
+
+		case jobsMode:
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "q":
+				m.closeJobs()
+			}
+			return m, nil
+
+		case dashboardMode:
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "q":
+				m.closeDashboard()
+				return m, nil
+			case "r":
+				return m, m.openDashboard()
+			}
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			return m, cmd
+
+		case detailMode:
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				if cmd, ok := m.backDetail(); ok {
+					return m, cmd
+				}
+				m.closeDetail()
+				return m, nil
+			case "q":
+				m.closeDetail()
+				return m, nil
+			case "enter":
+				if m.detailTab == relatedTab {
+					return m, m.followRelated()
+				}
+				m.closeDetail()
+				return m, nil
+			case "y", "Y":
+				m.notice = ""
+				return m, m.copySelected(msg.String() == "Y")
+			case "tab", "right", "l":
+				m.switchTab(1)
+				return m, nil
+			case "shift+tab", "left", "h":
+				m.switchTab(-1)
+				return m, nil
+			case "1", "2", "3", "4", "5", "6", "7":
+				m.detailTab = detailTab(msg.String()[0] - '1')
+				m.refreshDetail()
+				return m, nil
+			}
+			if m.detailTab == relatedTab {
+				m.relatedTable, cmd = m.relatedTable.Update(msg)
+				return m, cmd
+			}
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+	case liveSearchMsg:
+		// A newer keystroke or an explicit search superseded this one
+		if msg.seq != m.searchSeq || m.mode != searchMode || m.target != companyTarget {
+			return m, nil
+		}
+
+		// Half-typed filters are not errors yet, so they simply pause live search
+		sq, err := db.ParseSearchQuery(m.textInput.Value())
+		if err != nil || utf8.RuneCountInString(strings.TrimSpace(sq.Term)) < minLiveSearchLength {
+			m.setResults(nil)
+			m.liveResults = false
+			return m, nil
+		}
+		sq.Mode = db.SearchPrefix
+		sq.Limit = 10
+
+		m.searching = true
+		m.err = nil
+		m.warning = ""
+		return m, performSearch(m.pool, m.dbClient, sq, m.searchSeq, true)
+
+	case peopleResultMsg:
+		if msg.seq != m.searchSeq {
+			return m, nil
 		}
 
-	case searchResultMsg:
 		m.searching = false
-		m.results = msg.results
 		m.err = msg.err
+		if msg.err == nil {
+			m.setPeople(msg.results)
+		}
 
 		if msg.err == nil && len(msg.results) > 0 {
-			rows := make([]table.Row, len(msg.results))
-			for i, comp := range msg.results {
-				rows[i] = table.Row{
-					truncate(comp.Name, 35),
-					comp.TaxID,
-					comp.RegistrationCode,
-					truncate(comp.LegalForm, 20),
-				}
+			m.table.Focus()
+			m.mode = resultsMode
+			m.textInput.Blur()
+		}
+		return m, nil
+
+	case profileMsg:
+		m.setProfile(msg)
+		return m, nil
+
+	case relatedMsg:
+		m.setRelated(msg)
+		return m, nil
+
+	case caenVersionsMsg:
+		return m, m.setCAENVersions(msg)
+
+	case caenNodesMsg:
+		m.setCAENNodes(msg)
+		return m, nil
+
+	case jobsMsg:
+		return m, m.setJobs(msg)
+
+	case jobsTickMsg:
+		if msg.seq != m.jobsSeq || m.mode != jobsMode {
+			return m, nil
+		}
+		return m, fetchJobs(m.pool, m.dbClient, msg.seq)
+
+	case statsMsg:
+		m.setStats(msg)
+		return m, nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			m.notice = "Copy failed: " + msg.err.Error()
+		} else {
+			m.notice = fmt.Sprintf("Copied %s to the clipboard", msg.what)
+		}
+		return m, nil
+
+	case exportMsg:
+		if msg.err != nil {
+			m.notice = "Export failed: " + msg.err.Error()
+		} else {
+			m.notice = m.exportNotice(msg)
+		}
+		return m, nil
+
+	case searchResultMsg:
+		// Drop results of searches that were overtaken while in flight
+		if msg.seq != m.searchSeq {
+			return m, nil
+		}
+
+		m.searching = false
+		m.err = msg.err
+
+		if msg.more {
+			m.loadingMore = false
+			if msg.err == nil {
+				m.appendResults(msg.results)
+				m.nextCursor = msg.next
 			}
-			m.table.SetRows(rows)
+			return m, nil
+		}
+
+		m.liveResults = msg.live
+		if msg.err == nil {
+			m.setResults(msg.results)
+			m.nextCursor = msg.next
+			m.total = msg.total
+		}
+
+		if !msg.live && msg.err == nil && len(msg.results) > 0 {
 			m.table.Focus()
 			m.mode = resultsMode
 			m.textInput.Blur()
@@ -90,15 +340,65 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func performSearch(pool *pgxpool.Pool, dbClient *db.DB, searchTerm string) tea.Cmd {
+// setResults replaces the current results and the table rows showing them
+func (m *Model) setResults(results []goovern.Company) {
+	m.results = nil
+	m.table.SetRows(nil)
+	m.appendResults(results)
+	m.table.GotoTop()
+}
+
+// appendResults adds a further page of results below the current ones and moves onto it
+func (m *Model) appendResults(results []goovern.Company) {
+	hadResults := len(m.results) > 0
+	m.results = append(m.results, results...)
+
+	rows := make([]table.Row, len(m.results))
+	for i, comp := range m.results {
+		rows[i] = table.Row{
+			truncate(comp.Name, 35),
+			comp.TaxID,
+			comp.RegistrationCode,
+			truncate(comp.LegalForm, 20),
+		}
+	}
+	m.table.SetRows(rows)
+
+	if hadResults && len(results) > 0 {
+		m.table.MoveDown(1)
+	}
+}
+
+// debounceSearch schedules a live search once typing has paused for liveSearchDelay
+func debounceSearch(seq int) tea.Cmd {
+	return tea.Tick(liveSearchDelay, func(time.Time) tea.Msg {
+		return liveSearchMsg{seq: seq}
+	})
+}
+
+// performSearch fetches one page; the first page of an explicit search also counts all matches
+func performSearch(pool *pgxpool.Pool, dbClient *db.DB, sq db.SearchQuery, seq int, live bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		results, err := dbClient.Search(ctx, pool, searchTerm, 10)
-		return searchResultMsg{
-			results: results,
-			err:     err,
+		msg := searchResultMsg{seq: seq, live: live, more: sq.After != nil}
+
+		msg.results, msg.next, msg.err = dbClient.Search(ctx, pool, sq)
+		if msg.err != nil || live || msg.more {
+			return msg
 		}
+
+		msg.total, msg.err = dbClient.SearchCount(ctx, pool, sq)
+		return msg
+	}
+}
+
+// cuiWarning flags input that looks like a CUI but fails the check digit, which usually means a typo
+func cuiWarning(searchTerm string) string {
+	cui := goovern.NormalizeCUI(searchTerm)
+	if errors.Is(goovern.ValidateCUI(cui), goovern.ErrCUIChecksum) {
+		return fmt.Sprintf("CUI %s has an invalid check digit, showing partial matches", cui)
 	}
+	return ""
 }
 
 func truncate(s string, maxLen int) string {
@@ -8,8 +8,17 @@ import (
 )
 
 func (m Model) View() string {
-	if m.mode == modalMode && m.selectedCompany != nil {
-		return m.renderModal()
+	if m.mode == detailMode {
+		return m.renderDetail()
+	}
+	if m.mode == dashboardMode {
+		return m.renderDashboard()
+	}
+	if m.mode == caenMode {
+		return m.renderCAEN()
+	}
+	if m.mode == jobsMode {
+		return m.renderJobs()
 	}
 
 	var content strings.Builder
@@ -44,6 +53,9 @@ func (m Model) renderSearchView() string {
 	content.WriteString("\n\n")
 
 	subtitle := m.helpStyle.Render("Romanian Company Search")
+	if m.target == personTarget {
+		subtitle = m.helpStyle.Render("Romanian Company Search • Representatives")
+	}
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, subtitle))
 	content.WriteString("\n\n")
 
@@ -51,20 +63,35 @@ func (m Model) renderSearchView() string {
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, inputBox))
 	content.WriteString("\n\n")
 
+	if m.warning != "" {
+		content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.warningStyle.Render(m.warning)))
+		content.WriteString("\n")
+	}
+
 	var statusMsg string
 	if m.searching {
 		statusMsg = "Searching..."
 	} else if m.err != nil {
 		statusMsg = m.errorStyle.Render("Error: " + m.err.Error())
+	} else if m.target == personTarget && len(m.people) > 0 {
+		statusMsg = fmt.Sprintf("Found %d companies. Press Enter to view.", len(m.people))
+	} else if m.liveResults && len(m.results) > 0 {
+		statusMsg = fmt.Sprintf("%d matches as you type. Press Enter to search.", len(m.results))
 	} else if len(m.results) > 0 {
-		statusMsg = fmt.Sprintf("Found %d results. Press Enter to view.", len(m.results))
+		statusMsg = fmt.Sprintf("Found %d results. Press Enter to view.", m.total)
 	}
 	if statusMsg != "" {
 		content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, statusMsg))
 		content.WriteString("\n")
 	}
 
-	help := m.helpStyle.Render("enter: search • ctrl+c: quit")
+	if m.liveResults && len(m.results) > 0 {
+		tableView := m.borderStyle.Render(m.table.View())
+		content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, tableView))
+		content.WriteString("\n")
+	}
+
+	help := m.helpStyle.Render("type to search live • enter: search • tab: companies/representatives • ctrl+d: statistics • ctrl+b: CAEN codes • ctrl+t: jobs • ctrl+c: quit")
 	content.WriteString("\n")
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, help))
 
@@ -83,102 +110,95 @@ func (m Model) renderResultsView() string {
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, tableView))
 	content.WriteString("\n")
 
-	help := m.helpStyle.Render("↑/↓: navigate • enter: view details • esc: back to search • ctrl+c: quit")
+	if m.warning != "" {
+		content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.warningStyle.Render(m.warning)))
+		content.WriteString("\n")
+	}
+
+	count := fmt.Sprintf("%d of %d results", len(m.results), m.total)
+	if m.target == personTarget {
+		count = fmt.Sprintf("%d companies represented", len(m.people))
+	}
+	if m.loadingMore {
+		count += " • loading more..."
+	} else if m.err != nil {
+		count = m.errorStyle.Render("Error: " + m.err.Error())
+	}
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.helpStyle.Render(count)))
+	content.WriteString("\n")
+
+	if m.notice != "" {
+		content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.warningStyle.Render(m.notice)))
+		content.WriteString("\n")
+	}
+
+	help := m.helpStyle.Render("↑/↓: navigate • enter: view details • y/Y: copy JSON/CSV • e/E: export CSV/JSON • esc: back to search • ctrl+c: quit")
 	content.WriteString("\n")
 	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, help))
 
 	return content.String()
 }
 
-func (m Model) renderModal() string {
-	if m.selectedCompany == nil {
-		return ""
-	}
-
-	c := m.selectedCompany
+func (m Model) renderDetail() string {
 	var content strings.Builder
 
 	title := m.titleStyle.Render("  Company Details  ")
-	content.WriteString(lipgloss.PlaceHorizontal(60, lipgloss.Center, title))
+	if m.profile != nil {
+		title = m.titleStyle.Render("  " + truncate(m.profile.Name, m.detailWidth()-8) + "  ")
+	}
+	content.WriteString(lipgloss.PlaceHorizontal(m.detailWidth()-6, lipgloss.Center, title))
 	content.WriteString("\n\n")
+	help := "←/→ tab: switch tab • 1-7: jump to tab • ↑/↓: scroll • esc: close • ctrl+c: quit"
+	if len(m.detailHistory) > 0 {
+		help = "←/→ tab: switch tab • 1-7: jump to tab • ↑/↓: scroll • esc: back • q: close • ctrl+c: quit"
+	}
 
-	renderField := func(label, value string) {
-		if value != "" {
-			content.WriteString(m.labelStyle.Render(label) + "\n")
-			content.WriteString("  " + value + "\n\n")
-		}
-	}
-
-	renderField("Company Name", c.Name)
-	renderField("Tax ID (CUI)", c.TaxID)
-	renderField("Registration Code", c.RegistrationCode)
-	renderField("Registration Date", c.RegistrationDate)
-	renderField("Legal Form", c.LegalForm)
-	renderField("EUID", c.EUID)
-
-	if c.Country != "" || c.County != "" || c.Locality != "" {
-		content.WriteString(m.labelStyle.Render("Location") + "\n")
-		var location []string
-		if c.Locality != "" {
-			location = append(location, c.Locality)
-		}
-		if c.County != "" {
-			location = append(location, c.County)
-		}
-		if c.Country != "" {
-			location = append(location, c.Country)
-		}
-		if c.Sector != "" {
-			location = append(location, "Sector "+c.Sector)
-		}
-		content.WriteString("  " + strings.Join(location, ", ") + "\n\n")
-	}
-
-	if c.StreetName != "" || c.StreetNumber != "" || c.Building != "" ||
-		c.Staircase != "" || c.Floor != "" || c.Apartment != "" || c.PostalCode != "" {
-		content.WriteString(m.labelStyle.Render("Address") + "\n")
-		var address []string
-		if c.StreetName != "" {
-			streetAddr := c.StreetName
-			if c.StreetNumber != "" {
-				streetAddr += " " + c.StreetNumber
-			}
-			address = append(address, streetAddr)
-		}
-		if c.Building != "" {
-			address = append(address, "Building "+c.Building)
-		}
-		if c.Staircase != "" {
-			address = append(address, "Staircase "+c.Staircase)
-		}
-		if c.Floor != "" {
-			address = append(address, "Floor "+c.Floor)
-		}
-		if c.Apartment != "" {
-			address = append(address, "Apt "+c.Apartment)
-		}
-		if c.PostalCode != "" {
-			address = append(address, "Postal Code: "+c.PostalCode)
-		}
-		content.WriteString("  " + strings.Join(address, ", ") + "\n\n")
-	}
-
-	renderField("Address Details", c.AddressDetails)
-	renderField("Website", c.Website)
-	renderField("Parent Company Country", c.ParentCompanyCountry)
-
-	content.WriteString(m.labelStyle.Render("Search Rank Score") + "\n")
-	content.WriteString(fmt.Sprintf("  %.6f\n", c.Rank))
-
-	modalContent := m.modalStyle.Render(content.String())
-
-	help := m.helpStyle.Render("enter/esc: close • ctrl+c: quit")
+	if m.detailTab == relatedTab {
+		content.WriteString(m.renderRelated())
+		help = "↑/↓: select • enter: open company • ←/→ tab: switch tab • esc: back • q: close • ctrl+c: quit"
+	} else {
+		content.WriteString(m.viewport.View())
+	}
+
+	if m.detailTab != relatedTab && m.viewport.TotalLineCount() > m.viewport.VisibleLineCount() {
+		help = fmt.Sprintf("%3.f%% • %s", m.viewport.ScrollPercent()*100, help)
+	}
+
+	help += " • y/Y: copy JSON/CSV"
+	helpLine := m.helpStyle.Render(help)
+	if m.notice != "" {
+		helpLine = m.warningStyle.Render(m.notice)
+	}
 
 	var fullView strings.Builder
+	fullView.WriteString("\n")
+	fullView.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.renderTabs()))
 	fullView.WriteString("\n\n")
-	fullView.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, modalContent))
+	fullView.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.detailStyle.Width(m.detailWidth()).Render(content.String())))
 	fullView.WriteString("\n\n")
-	fullView.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, help))
+	fullView.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, helpLine))
 
 	return fullView.String()
 }
+
+func (m Model) renderDashboard() string {
+	var content strings.Builder
+
+	title := m.titleStyle.Render("  Registry Statistics  ")
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, title))
+	content.WriteString("\n")
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.dashboard.View()))
+	content.WriteString("\n")
+
+	help := "↑/↓: scroll • r: reload • esc: back to search • ctrl+c: quit"
+	if m.stats != nil && !m.stats.RefreshedAt.IsZero() {
+		help = fmt.Sprintf("as of %s • %s", m.stats.RefreshedAt.Format("2006-01-02 15:04"), help)
+	}
+	if m.loadingStats {
+		help = "loading... • " + help
+	}
+	content.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.helpStyle.Render(help)))
+
+	return content.String()
+}
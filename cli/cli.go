@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern/db"
+)
+
+const usage = `Usage: ssh <host> <command> [flags] [args]
+
+Commands:
+  search <term>    Search companies by name or CUI, with optional filters
+                   (county:, locality:, form:, caen:, status:, from:, to:, website:)
+  company <cui>    Show a company by CUI or registration code
+  lookup           Look up CUIs read from stdin, one per line
+  enrich           Enrich a CSV read from stdin with company details per CUI
+  imports [name]   List recent import runs, optionally of one resource (e.g. od_firme)
+  help             Show this help
+
+Flags:
+  --json           Write JSON instead of CSV
+  --limit <n>      Maximum number of search results or import runs, 1-100 (default 20)
+  --column <name>  enrich: header of the CUI column (default: first column, no header)
+  --delimiter <c>  enrich: field delimiter of the input CSV (default ",")
+`
+
+// maxLimit caps --limit like the API caps its limit parameter
+const maxLimit = 100
+
+var errUsage = errors.New("invalid usage")
+
+type command func(ctx context.Context, s ssh.Session, opts options, args []string) error
+
+type options struct {
+	json      bool
+	limit     int
+	column    string
+	delimiter string
+}
+
+type CLI struct {
+	pool     *pgxpool.Pool
+	db       *db.DB
+	logger   *slog.Logger
+	commands map[string]command
+}
+
+func New(pool *pgxpool.Pool, dbClient *db.DB, logger *slog.Logger) (*CLI, error) {
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
+	if dbClient == nil {
+		return nil, errors.New("db client required")
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	c := &CLI{
+		pool:   pool,
+		db:     dbClient,
+		logger: logger.With("component", "cli"),
+	}
+	c.commands = map[string]command{
+		"search":  c.search,
+		"company": c.company,
+		"lookup":  c.lookup,
+		"enrich":  c.enrich,
+		"imports": c.imports,
+	}
+
+	return c, nil
+}
+
+// Middleware runs non-interactive commands when the session carries one and
+// hands interactive sessions over to the next handler. It must be placed after
+// activeterm.Middleware so commands work without a PTY.
+func (c *CLI) Middleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			args := s.Command()
+			if len(args) == 0 {
+				next(s)
+				return
+			}
+
+			_ = s.Exit(c.run(s, args))
+		}
+	}
+}
+
+func (c *CLI) run(s ssh.Session, args []string) int {
+	name := args[0]
+	if name == "help" || name == "--help" || name == "-h" {
+		wish.Print(s, usage)
+		return 0
+	}
+
+	cmd, ok := c.commands[name]
+	if !ok {
+		wish.Errorf(s, "unknown command: %s\n\n%s", name, usage)
+		return 2
+	}
+
+	opts, rest, err := parseArgs(args[1:], s.Stderr())
+	if err != nil {
+		wish.Error(s, usage)
+		return 2
+	}
+
+	logger := c.logger.With("command", name, "user", s.User())
+	logger.Info("Running command", "args", rest)
+
+	if err = cmd(s.Context(), s, opts, rest); err != nil {
+		if errors.Is(err, errUsage) {
+			wish.Error(s, usage)
+			return 2
+		}
+		logger.Error("Command failed", "error", err)
+		wish.Errorln(s, "error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// parseArgs parses flags interspersed with positional arguments
+func parseArgs(args []string, output io.Writer) (options, []string, error) {
+	var opts options
+	fs := flag.NewFlagSet("goovern", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.BoolVar(&opts.json, "json", false, "write JSON instead of CSV")
+	fs.IntVar(&opts.limit, "limit", 20, "maximum number of search results")
+	fs.StringVar(&opts.column, "column", "", "header of the CUI column")
+	fs.StringVar(&opts.delimiter, "delimiter", ",", "field delimiter of the input CSV")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return opts, nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if opts.limit < 1 {
+		fmt.Fprintln(output, "limit must be a positive integer")
+		return opts, nil, errUsage
+	}
+	opts.limit = min(opts.limit, maxLimit)
+
+	return opts, positional, nil
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/export"
+)
+
+func (c *CLI) search(ctx context.Context, s ssh.Session, opts options, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	sq, err := db.ParseSearchQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	sq.Limit = opts.limit
+
+	results, _, err := c.db.Search(ctx, c.pool, sq)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		return export.WriteJSON(s, nonNil(results))
+	}
+	return export.WriteCSV(s, export.CompanyHeader, results, export.CompanyRecord)
+}
+
+func (c *CLI) company(ctx context.Context, s ssh.Session, opts options, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	comp, found, err := c.findCompany(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("company not found: %s", args[0])
+	}
+
+	if opts.json {
+		return export.WriteJSON(s, comp)
+	}
+	return export.WriteCSV(s, export.CompanyHeader, []goovern.Company{comp}, export.CompanyRecord)
+}
+
+// findCompany resolves a registration code (which always contains a slash) or a CUI
+func (c *CLI) findCompany(ctx context.Context, key string) (goovern.Company, bool, error) {
+	if strings.Contains(key, "/") {
+		return c.db.Company(ctx, c.pool, key)
+	}
+	return c.db.CompanyByTaxID(ctx, c.pool, key)
+}
+
+// lookupResult is one line of lookup output, reporting CUIs that matched no company
+type lookupResult struct {
+	Query   string           `json:"query"`
+	Found   bool             `json:"found"`
+	Company *goovern.Company `json:"company,omitempty"`
+}
+
+// lookup streams one result per CUI read from stdin, as CSV rows or JSON lines. CUIs are
+// resolved in batches of batchSize.
+func (c *CLI) lookup(ctx context.Context, s ssh.Session, opts options, _ []string) error {
+	w := csv.NewWriter(s)
+	enc := json.NewEncoder(s)
+
+	if !opts.json {
+		if err := w.Write(append([]string{"query", "found"}, export.CompanyHeader...)); err != nil {
+			return err
+		}
+	}
+
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		companies, err := c.db.CompaniesByTaxIDs(ctx, c.pool, batch)
+		if err != nil {
+			return err
+		}
+
+		for _, cui := range batch {
+			comp, found := companies[goovern.NormalizeCUI(cui)]
+
+			if opts.json {
+				res := lookupResult{Query: cui, Found: found}
+				if found {
+					res.Company = &comp
+				}
+				if err = enc.Encode(res); err != nil {
+					return err
+				}
+				continue
+			}
+
+			record := []string{cui, fmt.Sprint(found)}
+			if found {
+				record = append(record, export.CompanyRecord(comp)...)
+			} else {
+				record = append(record, make([]string, len(export.CompanyHeader))...)
+			}
+			if err = w.Write(record); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+
+		w.Flush()
+		return w.Error()
+	}
+
+	scanner := bufio.NewScanner(s)
+	for scanner.Scan() {
+		cui := strings.TrimSpace(scanner.Text())
+		if cui == "" {
+			continue
+		}
+
+		batch = append(batch, cui)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// imports lists recent import runs, newest first
+func (c *CLI) imports(ctx context.Context, s ssh.Session, opts options, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+	var resource string
+	if len(args) == 1 {
+		resource = args[0]
+	}
+
+	runs, err := c.db.ImportRuns(ctx, c.pool, resource, opts.limit)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		return export.WriteJSON(s, nonNil(runs))
+	}
+	return export.WriteCSV(s, export.ImportRunHeader, runs, export.ImportRunRecord)
+}
+
+// nonNil makes empty results encode as [] rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
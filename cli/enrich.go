@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/ssh"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// batchSize bounds how many CUIs are resolved per query while streaming
+const batchSize = 500
+
+var enrichHeader = []string{"found", "tax_id", "name", "registration_code", "status", "county", "legal_form", "first_authorized_caen"}
+
+// enrich reads a CSV from stdin and streams every record back with company details appended
+func (c *CLI) enrich(ctx context.Context, s ssh.Session, opts options, _ []string) error {
+	delimiter, size := utf8.DecodeRuneInString(opts.delimiter)
+	if size == 0 || size != len(opts.delimiter) {
+		return errors.New("delimiter must be a single character")
+	}
+
+	r := csv.NewReader(s)
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+
+	w := csv.NewWriter(s)
+	w.Comma = delimiter
+
+	column := 0
+	if opts.column != "" {
+		header, err := r.Read()
+		if err != nil {
+			return fmt.Errorf("reading header: %w", err)
+		}
+		if column = slices.Index(header, opts.column); column < 0 {
+			return fmt.Errorf("column not found in header: %s", opts.column)
+		}
+		if err = w.Write(append(header, enrichHeader...)); err != nil {
+			return err
+		}
+	}
+
+	var batch [][]string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		taxIDs := make([]string, len(batch))
+		for i, record := range batch {
+			if column < len(record) {
+				taxIDs[i] = record[column]
+			}
+		}
+
+		enriched, err := c.db.LookupTaxIDs(ctx, c.pool, taxIDs)
+		if err != nil {
+			return err
+		}
+
+		for i, record := range batch {
+			if err = w.Write(append(record, enrichmentRecord(enriched[i])...)); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+
+		w.Flush()
+		return w.Error()
+	}
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading CSV: %w", err)
+		}
+
+		batch = append(batch, record)
+		if len(batch) == batchSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+func enrichmentRecord(e goovern.Enrichment) []string {
+	return []string{
+		fmt.Sprint(e.Found),
+		e.TaxID,
+		e.Name,
+		e.RegistrationCode,
+		e.Status,
+		e.County,
+		e.LegalForm,
+		e.FirstAuthorizedCAEN,
+	}
+}
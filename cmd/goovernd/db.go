@@ -8,10 +8,11 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/ionut-maxim/goovern/config"
 	"github.com/ionut-maxim/goovern/db"
 )
 
-func newDB(databaseURL string, logger *slog.Logger) (*pgxpool.Pool, *db.DB, error) {
+func newDB(databaseURL string, importConfig config.Import, logger *slog.Logger) (*pgxpool.Pool, *db.DB, error) {
 	pool, err := pgxpool.New(context.Background(), databaseURL)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create pool: %v", err)
@@ -23,5 +24,22 @@ func newDB(databaseURL string, logger *slog.Logger) (*pgxpool.Pool, *db.DB, erro
 		return nil, nil, fmt.Errorf("failed to migrate: %v", err)
 	}
 
-	return pool, db.New(logger), nil
+	dbClient := db.New(logger)
+	dbClient.SetCopyParallelism(importConfig.Parallelism)
+	dbClient.SetBulkLoad(importConfig.BulkLoad)
+	dbClient.SetFullReload(importConfig.FullReload)
+	if importConfig.Config != "" {
+		if err = dbClient.LoadImportConfigs(importConfig.Config); err != nil {
+			return nil, nil, fmt.Errorf("failed to load import configs: %v", err)
+		}
+	}
+	if err = dbClient.ValidateImportConfigs(migrateCtx, pool); err != nil {
+		return nil, nil, fmt.Errorf("invalid import configs: %v", err)
+	}
+
+	if _, err = dbClient.LoadCAENCorrespondence(migrateCtx, pool, importConfig.CAENCorrespondence); err != nil {
+		return nil, nil, fmt.Errorf("failed to load CAEN correspondence: %v", err)
+	}
+
+	return pool, dbClient, nil
 }
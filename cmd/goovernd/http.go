@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern/admin"
+	"github.com/ionut-maxim/goovern/api"
+	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/metrics"
+)
+
+func startAdminServer(pool *pgxpool.Pool, db *db.DB, port int, logger *slog.Logger, done chan<- os.Signal) (*http.Server, error) {
+	handler, err := admin.NewHandler(pool, db, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating admin handler: %w", err)
+	}
+
+	seedCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	imports, err := db.LastImports(seedCtx, pool)
+	if err != nil {
+		logger.Warn("Could not load last import times", "error", err)
+	}
+	for name, at := range imports {
+		metrics.LastImport.WithLabelValues(name).Set(float64(at.Unix()))
+	}
+
+	logger.Info("Starting admin HTTP server", "host", "", "port", port)
+	return startHTTPServer(handler, port, logger, done), nil
+}
+
+func startAPIServer(pool *pgxpool.Pool, db *db.DB, port int, logger *slog.Logger, done chan<- os.Signal) (*http.Server, error) {
+	handler, err := api.NewHandler(pool, db, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating API handler: %w", err)
+	}
+
+	logger.Info("Starting API HTTP server", "host", "", "port", port)
+	return startHTTPServer(handler, port, logger, done), nil
+}
+
+func startHTTPServer(handler http.Handler, port int, logger *slog.Logger, done chan<- os.Signal) *http.Server {
+	s := &http.Server{
+		Addr:              net.JoinHostPort("", strconv.Itoa(port)),
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Could not start HTTP server", "addr", s.Addr, "error", err)
+			done <- nil
+		}
+	}()
+
+	return s
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -31,7 +32,7 @@ func main() {
 
 	logger := cfg.Log.New()
 
-	pool, db, err := newDB(cfg.DB.Url, logger)
+	pool, db, err := newDB(cfg.DB.Url, cfg.Import, logger)
 	if err != nil {
 		slog.Error("failed to create connection pool", "error", err)
 		os.Exit(1)
@@ -44,11 +45,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	stopObserving := worker.ObserveJobs(wrk)
+	defer stopObserving()
+
 	if err = wrk.Start(workerCtx); err != nil {
 		slog.Error("failed to start worker", "error", err)
 		os.Exit(1)
 	}
-	s := startSSHServer(pool, db, 42069, logger, done)
+	s, err := startSSHServer(pool, db, 42069, logger, done)
+	if err != nil {
+		slog.Error("failed to start SSH server", "error", err)
+		os.Exit(1)
+	}
+	h, err := startAdminServer(pool, db, cfg.Admin.Port, logger, done)
+	if err != nil {
+		slog.Error("failed to start admin server", "error", err)
+		os.Exit(1)
+	}
+	a, err := startAPIServer(pool, db, cfg.API.Port, logger, done)
+	if err != nil {
+		slog.Error("failed to start API server", "error", err)
+		os.Exit(1)
+	}
 
 	<-done
 
@@ -63,10 +81,19 @@ func main() {
 	if err = wrk.Stop(shutdownCtx); err != nil {
 		logger.Error("Could not stop worker", "error", err)
 	}
-	logger.Info("Closing database connection")
-	pool.Close()
+	logger.Info("Stopping API HTTP server")
+	if err = a.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Could not stop API server", "error", err)
+	}
+	logger.Info("Stopping admin HTTP server")
+	if err = h.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Could not stop admin server", "error", err)
+	}
 	logger.Info("Stopping SSH server")
 	if err = s.Shutdown(shutdownCtx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 		logger.Error("Could not stop server", "error", err)
 	}
+	// Closed last: the servers above use the pool until they stop
+	logger.Info("Closing database connection")
+	pool.Close()
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"os"
@@ -14,33 +15,58 @@ import (
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
 	"github.com/charmbracelet/wish/ratelimiter"
+	"github.com/charmbracelet/wish/scp"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/ionut-maxim/goovern/app"
+	"github.com/ionut-maxim/goovern/cli"
 	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/export"
+	"github.com/ionut-maxim/goovern/metrics"
 )
 
-func makeTeaHandler(pool *pgxpool.Pool, dbClient *db.DB) func(ssh.Session) (tea.Model, []tea.ProgramOption) {
+func makeTeaHandler(pool *pgxpool.Pool, dbClient *db.DB, exports *export.Store) func(ssh.Session) (tea.Model, []tea.ProgramOption) {
 	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-		return app.NewModel(s, pool, dbClient)
+		return app.NewModel(s, pool, dbClient, exports)
 	}
 }
 
-func startSSHServer(pool *pgxpool.Pool, db *db.DB, port int, logger *slog.Logger, done chan<- os.Signal) *ssh.Server {
+func sessionMetricsMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			metrics.SSHSessions.Inc()
+			next(s)
+		}
+	}
+}
+
+func startSSHServer(pool *pgxpool.Pool, db *db.DB, port int, logger *slog.Logger, done chan<- os.Signal) (*ssh.Server, error) {
 	rateLimiter := ratelimiter.NewRateLimiter(2.0, 5, 200)
 
+	commands, err := cli.New(pool, db, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating SSH commands: %w", err)
+	}
+
+	// Exports made in the TUI are fetched over SCP or SFTP from a separate connection
+	exports := export.NewStore()
+
 	s, err := wish.NewServer(
 		wish.WithAddress(net.JoinHostPort("", strconv.Itoa(port))),
 		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		wish.WithSubsystem("sftp", exports.SFTPHandler(logger)),
 		wish.WithMiddleware(
-			bubbletea.Middleware(makeTeaHandler(pool, db)),
-			activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
+			bubbletea.Middleware(makeTeaHandler(pool, db, exports)),
+			activeterm.Middleware(),                   // Bubble Tea apps usually require a PTY.
+			commands.Middleware(),                     // Non-interactive commands run without one.
+			scp.Middleware(exports.SCPHandler(), nil), // So do SCP downloads.
 			ratelimiter.Middleware(rateLimiter),
 			logging.Middleware(),
+			sessionMetricsMiddleware(),
 		),
 	)
 	if err != nil {
-		logger.Error("Could not start server", "error", err)
+		return nil, fmt.Errorf("creating SSH server: %w", err)
 	}
 
 	logger.Info("Starting SSH server", "host", "", "port", port)
@@ -51,5 +77,5 @@ func startSSHServer(pool *pgxpool.Pool, db *db.DB, port int, logger *slog.Logger
 		}
 	}()
 
-	return s
+	return s, nil
 }
@@ -1,5 +1,7 @@
 package goovern
 
+import "time"
+
 type Company struct {
 	TaxID                string  `json:"tax_id" db:"tax_id"`
 	Name                 string  `json:"name" db:"name"`
@@ -23,3 +25,129 @@ type Company struct {
 	ParentCompanyCountry string  `json:"parent_company_country" db:"parent_company_country"`
 	Rank                 float32 `json:"rank" db:"rank"`
 }
+
+type LegalRepresentative struct {
+	RegistrationCode string `json:"registration_code" db:"registration_code"`
+	AuthorizedPerson string `json:"authorized_person" db:"authorized_person"`
+	Role             string `json:"role" db:"role"`
+	BirthDate        string `json:"birth_date" db:"birth_date"`
+	BirthLocality    string `json:"birth_locality" db:"birth_locality"`
+	BirthCounty      string `json:"birth_county" db:"birth_county"`
+	BirthCountry     string `json:"birth_country" db:"birth_country"`
+	Locality         string `json:"locality" db:"locality"`
+	County           string `json:"county" db:"county"`
+	Country          string `json:"country" db:"country"`
+}
+
+type FamilyBusinessRepresentative struct {
+	RegistrationCode string `json:"registration_code" db:"registration_code"`
+	Name             string `json:"name" db:"name"`
+	Role             string `json:"role" db:"role"`
+	BirthDate        string `json:"birth_date" db:"birth_date"`
+	BirthLocality    string `json:"birth_locality" db:"birth_locality"`
+	BirthCounty      string `json:"birth_county" db:"birth_county"`
+	BirthCountry     string `json:"birth_country" db:"birth_country"`
+}
+
+type AuthorizedActivity struct {
+	RegistrationCode string `json:"registration_code" db:"registration_code"`
+	CAENCode         string `json:"caen_code" db:"caen_code"`
+	CAENVersion      int    `json:"caen_version" db:"caen_version"`
+	CAENName         string `json:"caen_name" db:"caen_name"`
+}
+
+type CompanyStatus struct {
+	RegistrationCode string `json:"registration_code" db:"registration_code"`
+	Code             int    `json:"code" db:"code"`
+	Name             string `json:"name" db:"name"`
+}
+
+type ForeignBranch struct {
+	RegistrationCode string `json:"registration_code" db:"registration_code"`
+	UnitType         string `json:"unit_type" db:"unit_type"`
+	BranchName       string `json:"branch_name" db:"branch_name"`
+	EUID             string `json:"euid" db:"euid"`
+	TaxCode          string `json:"tax_code" db:"tax_code"`
+	Country          string `json:"country" db:"country"`
+}
+
+// Enrichment summarizes the company matched by a bulk tax ID lookup
+type Enrichment struct {
+	Query               string `json:"query" db:"query"`
+	Found               bool   `json:"found" db:"found"`
+	TaxID               string `json:"tax_id" db:"tax_id"`
+	Name                string `json:"name" db:"name"`
+	RegistrationCode    string `json:"registration_code" db:"registration_code"`
+	Status              string `json:"status" db:"status"`
+	County              string `json:"county" db:"county"`
+	LegalForm           string `json:"legal_form" db:"legal_form"`
+	FirstAuthorizedCAEN string `json:"first_authorized_caen" db:"first_authorized_caen"` // Lowest authorized class of the newest CAEN version; ONRC records no main activity
+}
+
+// Representation links a person to a company they represent
+type Representation struct {
+	Person           string  `json:"person" db:"person"`
+	BirthDate        string  `json:"birth_date" db:"birth_date"`
+	Role             string  `json:"role" db:"role"`
+	Kind             string  `json:"kind" db:"kind"` // legal or family_business
+	RegistrationCode string  `json:"registration_code" db:"registration_code"`
+	CompanyName      string  `json:"company_name" db:"company_name"`
+	TaxID            string  `json:"tax_id" db:"tax_id"`
+	Rank             float32 `json:"rank" db:"rank"`
+}
+
+// CompanyProfile is a company together with all of its related registry records
+type CompanyProfile struct {
+	Company
+	LegalRepresentatives          []LegalRepresentative          `json:"legal_representatives"`
+	FamilyBusinessRepresentatives []FamilyBusinessRepresentative `json:"family_business_representatives"`
+	Activities                    []AuthorizedActivity           `json:"activities"`
+	StatusHistory                 []CompanyStatus                `json:"status_history"`
+	ForeignBranches               []ForeignBranch                `json:"foreign_branches"`
+}
+
+// RelatedCompany is a company linked to another through a shared representative or registered address
+type RelatedCompany struct {
+	Company
+	Reason string `json:"reason" db:"reason"` // representative or address
+	Link   string `json:"link" db:"link"`     // the shared person or address
+}
+
+// StatCount is the number of companies in one bucket of a registry statistic
+type StatCount struct {
+	Label     string `json:"label" db:"label"`
+	Companies int64  `json:"companies" db:"companies"`
+}
+
+// RegistryStats aggregates the registry as of the last statistics refresh
+type RegistryStats struct {
+	Companies          int64       `json:"companies"`
+	Active             int64       `json:"active"`
+	Radiated           int64       `json:"radiated"`
+	Other              int64       `json:"other"`
+	RefreshedAt        time.Time   `json:"refreshed_at"`
+	CAENVersion        int         `json:"caen_version"`
+	ByCounty           []StatCount `json:"by_county"`
+	ByLegalForm        []StatCount `json:"by_legal_form"`
+	ByCAENSection      []StatCount `json:"by_caen_section"`
+	ByRegistrationYear []StatCount `json:"by_registration_year"`
+}
+
+// CAEN versions as coded in caen_versions and authorized_activities
+const (
+	CAENRev2 = 2
+	CAENRev3 = 3
+)
+
+type CAENVersion struct {
+	Code        int    `json:"code" db:"code"`
+	Description string `json:"description" db:"description"`
+}
+
+// CAENNode is a section, division, group or class of the CAEN classification
+type CAENNode struct {
+	Level     string `json:"level" db:"level"` // section, division, group or class
+	Code      string `json:"code" db:"code"`
+	Name      string `json:"name" db:"name"`
+	Companies int64  `json:"companies" db:"companies"`
+}
@@ -1,9 +1,18 @@
 package csv
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 type ProgressCallback func(rowCount int64)
 
+// Transform converts a raw field into the value copied for it; nil is copied as NULL
+type Transform func(value string) (any, error)
+
+// RejectCallback is invoked for every field a Transform failed on. The field is copied as NULL.
+type RejectCallback func(line int64, field int, value string, err error)
+
 func NewSource(data *GoovernReader) *Source {
 	return &Source{
 		reader: data,
@@ -17,6 +26,22 @@ func (c *Source) WithProgressCallback(callback ProgressCallback, interval int64)
 	return c
 }
 
+// WithTransforms converts every emitted field with the transform at its position; a nil
+// transform passes the raw string through
+func (c *Source) WithTransforms(transforms []Transform, onReject RejectCallback) *Source {
+	c.transforms = transforms
+	c.rejectCallback = onReject
+	return c
+}
+
+// WithFields checks that every row has width fields and emits only those at the given
+// positions, in order. A nil fields emits all of them.
+func (c *Source) WithFields(width int, fields []int) *Source {
+	c.width = width
+	c.fields = fields
+	return c
+}
+
 // Source implements pgx.CopyFromSource for streaming CSV data
 type Source struct {
 	reader           Reader
@@ -25,6 +50,10 @@ type Source struct {
 	rowCount         int64
 	progressCallback ProgressCallback
 	progressInterval int64
+	transforms       []Transform
+	rejectCallback   RejectCallback
+	width            int
+	fields           []int
 }
 
 func (c *Source) Next() bool {
@@ -49,13 +78,43 @@ func (c *Source) Values() ([]any, error) {
 		return nil, c.readErr
 	}
 
-	values := make([]any, len(c.currentRow))
-	for i, v := range c.currentRow {
-		values[i] = v
+	if c.width > 0 && len(c.currentRow) != c.width {
+		// The header is line 1
+		return nil, fmt.Errorf("line %d has %d fields, expected %d", c.rowCount+1, len(c.currentRow), c.width)
+	}
+
+	row := c.currentRow
+	if c.fields != nil {
+		row = make([]string, len(c.fields))
+		for i, f := range c.fields {
+			row[i] = c.currentRow[f]
+		}
+	}
+
+	values := make([]any, len(row))
+	for i, v := range row {
+		if c.transforms == nil || c.transforms[i] == nil {
+			values[i] = v
+			continue
+		}
+
+		value, err := c.transforms[i](v)
+		if err != nil {
+			value = nil
+			if c.rejectCallback != nil {
+				c.rejectCallback(c.rowCount+1, i, v, err)
+			}
+		}
+		values[i] = value
 	}
 	return values, nil
 }
 
+// Rows returns the number of rows read so far
+func (c *Source) Rows() int64 {
+	return c.rowCount
+}
+
 func (c *Source) Err() error {
 	if c.readErr == io.EOF {
 		return nil
@@ -0,0 +1,53 @@
+package goovern
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCUIFormat   = errors.New("CUI must have between 2 and 10 digits")
+	ErrCUIChecksum = errors.New("CUI has an invalid check digit")
+)
+
+// cuiKey is the ANAF test key, right-aligned against the CUI digits preceding the control digit
+const cuiKey = "753217532"
+
+// NormalizeCUI strips whitespace and the optional RO VAT prefix from a CUI/CIF
+func NormalizeCUI(s string) string {
+	s = strings.Join(strings.Fields(s), "")
+	if len(s) >= 2 && strings.EqualFold(s[:2], "RO") {
+		s = s[2:]
+	}
+	return s
+}
+
+// ValidateCUI checks a normalized CUI against the official control-digit algorithm
+func ValidateCUI(cui string) error {
+	if len(cui) < 2 || len(cui) > 10 {
+		return ErrCUIFormat
+	}
+	for _, r := range cui {
+		if r < '0' || r > '9' {
+			return ErrCUIFormat
+		}
+	}
+
+	body := cui[:len(cui)-1]
+	key := cuiKey[len(cuiKey)-len(body):]
+
+	sum := 0
+	for i := range body {
+		sum += int(body[i]-'0') * int(key[i]-'0')
+	}
+
+	control := sum * 10 % 11
+	if control == 10 {
+		control = 0
+	}
+	if int(cui[len(cui)-1]-'0') != control {
+		return ErrCUIChecksum
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package goovern
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCUI(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{input: "14399840", want: "14399840"},
+		{input: "RO14399840", want: "14399840"},
+		{input: "ro14399840", want: "14399840"},
+		{input: "Ro14399840", want: "14399840"},
+		{input: " RO 1439 9840\t", want: "14399840"},
+		{input: "RO", want: ""},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeCUI(tt.input); got != tt.want {
+			t.Errorf("NormalizeCUI(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCUI(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{name: "valid", input: "14399840"},
+		{name: "RO prefix", input: "RO14399840"},
+		{name: "lower case prefix", input: "ro14399840"},
+		{name: "embedded spaces", input: "RO 143 998 40"},
+		{name: "shortest", input: "19"},
+		{name: "longest", input: "1234567897"},
+		{name: "control digit 10 becomes 0", input: "60"},
+		{name: "wrong check digit", input: "14399841", want: ErrCUIChecksum},
+		{name: "wrong check digit with prefix", input: "RO14399849", want: ErrCUIChecksum},
+		{name: "empty", input: "", want: ErrCUIFormat},
+		{name: "prefix only", input: "RO", want: ErrCUIFormat},
+		{name: "single digit", input: "7", want: ErrCUIFormat},
+		{name: "longer than 10 digits", input: "12345678901", want: ErrCUIFormat},
+		{name: "non-numeric", input: "1439984A", want: ErrCUIFormat},
+		{name: "other prefix", input: "BG14399840", want: ErrCUIFormat},
+		{name: "dash", input: "RO-14399840", want: ErrCUIFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCUI(NormalizeCUI(tt.input)); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateCUI(NormalizeCUI(%q)) = %v, want %v", tt.input, err, tt.want)
+			}
+		})
+	}
+}
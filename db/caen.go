@@ -0,0 +1,184 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// CAENLevels are the levels of the CAEN classification, from the top down
+var CAENLevels = []string{"section", "division", "group", "class"}
+
+// CAENVersions lists the imported CAEN versions, oldest first
+func (c *DB) CAENVersions(ctx context.Context, db Querier) ([]goovern.CAENVersion, error) {
+	q := `SELECT code, description FROM caen_versions ORDER BY code`
+	return collect[goovern.CAENVersion](ctx, db, q)
+}
+
+// CAENChildren lists the nodes one level below parent, which is a node of the level above
+// or empty for the sections. Nodes are named from their own row of caen_codes, the one
+// with the levels below left empty, and carry the companies counted at the last stats refresh.
+func (c *DB) CAENChildren(ctx context.Context, db Querier, version int, level string, parent string) ([]goovern.CAENNode, error) {
+	depth := -1
+	for i, l := range CAENLevels {
+		if l == level {
+			depth = i
+		}
+	}
+	if depth < 0 {
+		return nil, fmt.Errorf("unknown CAEN level: %s", level)
+	}
+
+	column := func(l string) string { return fmt.Sprintf(`COALESCE(cc.%q, '')`, l) }
+
+	// Sections have no parent, but $2 must still be referenced for its type to be known
+	parentCondition := "$2::text = ''"
+	if depth > 0 {
+		parentCondition = column(CAENLevels[depth-1]) + " = $2"
+	}
+
+	// The node's own row leaves every level below it empty
+	ownRow := column(level) + " = n.code"
+	for _, l := range CAENLevels[depth+1:] {
+		ownRow += " AND " + column(l) + " = ''"
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		$3::text AS level,
+		n.code,
+		COALESCE(named.name, '') AS name,
+		COALESCE(s.companies, 0) AS companies
+	FROM (
+		SELECT DISTINCT %[1]s AS code
+		FROM caen_codes cc
+		WHERE cc.caen_version = $1 AND %[1]s <> '' AND %[2]s
+	) n
+	LEFT JOIN LATERAL (
+		SELECT cc.name
+		FROM caen_codes cc
+		WHERE cc.caen_version = $1 AND %[3]s
+		LIMIT 1
+	) named ON true
+	LEFT JOIN stats_by_caen_code s ON s.caen_version = $1 AND s.level = $3 AND s.code = n.code
+	ORDER BY n.code
+	`, column(level), parentCondition, ownRow)
+
+	return collect[goovern.CAENNode](ctx, db, q, version, parent, level)
+}
+
+// caenCorrespondence lists Rev.2 classes whose Rev.3 counterpart has another code or name; those
+// unchanged between the versions are matched from caen_codes by the caen_translations view
+//
+//go:embed data/caen_rev2_rev3.csv
+var caenCorrespondence []byte
+
+// LoadCAENCorrespondence replaces the Rev.2 to Rev.3 correspondence with the mapping read
+// from path, or the partial one bundled in the binary when path is empty, and returns the
+// number of pairs loaded. A mapping already loaded is left as is and 0 is returned.
+func (c *DB) LoadCAENCorrespondence(ctx context.Context, db Tx, path string) (int64, error) {
+	data := caenCorrespondence
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return 0, fmt.Errorf("failed to read CAEN correspondence: %w", err)
+		}
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse CAEN correspondence: %w", err)
+	}
+	if len(records) > 0 {
+		records = records[1:] // header
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Replicas starting together would otherwise each replace the mapping
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('goovern_caen_correspondence'))`); err != nil {
+		return 0, fmt.Errorf("failed to lock CAEN correspondence: %w", err)
+	}
+
+	var loaded bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM caen_correspondence_source WHERE checksum = $1)`, checksum).Scan(&loaded)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check loaded CAEN correspondence: %w", err)
+	}
+	if loaded {
+		c.logger.Debug("CAEN correspondence already loaded", "checksum", checksum)
+		return 0, nil
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM caen_correspondence`); err != nil {
+		return 0, fmt.Errorf("failed to clear CAEN correspondence: %w", err)
+	}
+
+	n, err := tx.CopyFrom(ctx, pgx.Identifier{"caen_correspondence"}, []string{"rev2_code", "rev3_code"},
+		pgx.CopyFromSlice(len(records), func(i int) ([]any, error) {
+			return []any{strings.TrimSpace(records[i][0]), strings.TrimSpace(records[i][1])}, nil
+		}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy CAEN correspondence: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM caen_correspondence_source`); err != nil {
+		return 0, fmt.Errorf("failed to clear CAEN correspondence checksum: %w", err)
+	}
+	if _, err = tx.Exec(ctx, `INSERT INTO caen_correspondence_source (checksum) VALUES ($1)`, checksum); err != nil {
+		return 0, fmt.Errorf("failed to record CAEN correspondence checksum: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit CAEN correspondence: %w", err)
+	}
+
+	if path == "" {
+		c.logger.Warn("Loaded the bundled CAEN correspondence, changed classes it misses are not translated", "pairs", n)
+	} else {
+		c.logger.Info("Loaded CAEN correspondence", "path", path, "pairs", n)
+	}
+	return n, nil
+}
+
+// TranslateCAEN returns the classes of version to corresponding to a class of version from.
+// A class split or merged between versions yields several codes; none means no known match.
+func (c *DB) TranslateCAEN(ctx context.Context, db Querier, code string, from, to int) ([]string, error) {
+	q := `
+	SELECT to_code
+	FROM caen_translations
+	WHERE from_version = $1 AND from_code = $2 AND to_version = $3
+	ORDER BY to_code
+	`
+	rows, err := db.Query(ctx, q, from, code, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	codes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+	return codes, nil
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// companyColumns is the select list matching scanCompany, with NULLs flattened to empty strings
+// and dates formatted as YYYY-MM-DD
+const companyColumns = `
+	registration_code,
+	name,
+	COALESCE(tax_id, '') AS tax_id,
+	COALESCE(to_char(registration_date, 'YYYY-MM-DD'), '') AS registration_date,
+	COALESCE(euid, '') AS euid,
+	COALESCE(legal_form, '') AS legal_form,
+	COALESCE(country, '') AS country,
+	COALESCE(county, '') AS county,
+	COALESCE(locality, '') AS locality,
+	COALESCE(street_name, '') AS street_name,
+	COALESCE(street_number, '') AS street_number,
+	COALESCE(building, '') AS building,
+	COALESCE(staircase, '') AS staircase,
+	COALESCE(floor, '') AS floor,
+	COALESCE(apartment, '') AS apartment,
+	COALESCE(postal_code, '') AS postal_code,
+	COALESCE(sector, '') AS sector,
+	COALESCE(address_details, '') AS address_details,
+	COALESCE(website, '') AS website,
+	COALESCE(parent_company_country, '') AS parent_company_country`
+
+// scanCompany scans a row selected with companyColumns, followed by any extra columns
+func scanCompany(row pgx.Row, comp *goovern.Company, extra ...any) error {
+	dest := []any{
+		&comp.RegistrationCode,
+		&comp.Name,
+		&comp.TaxID,
+		&comp.RegistrationDate,
+		&comp.EUID,
+		&comp.LegalForm,
+		&comp.Country,
+		&comp.County,
+		&comp.Locality,
+		&comp.StreetName,
+		&comp.StreetNumber,
+		&comp.Building,
+		&comp.Staircase,
+		&comp.Floor,
+		&comp.Apartment,
+		&comp.PostalCode,
+		&comp.Sector,
+		&comp.AddressDetails,
+		&comp.Website,
+		&comp.ParentCompanyCountry,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// Company returns the company with the given registration code
+func (c *DB) Company(ctx context.Context, db Querier, registrationCode string) (goovern.Company, bool, error) {
+	q := `SELECT ` + companyColumns + ` FROM companies WHERE registration_code = $1`
+	return c.company(ctx, db, q, registrationCode)
+}
+
+// CompanyByTaxID returns the company registered under the given tax ID (CUI), with or without the RO prefix
+func (c *DB) CompanyByTaxID(ctx context.Context, db Querier, taxID string) (goovern.Company, bool, error) {
+	q := `SELECT ` + companyColumns + ` FROM companies WHERE tax_id = $1 ORDER BY registration_code LIMIT 1`
+	return c.company(ctx, db, q, goovern.NormalizeCUI(taxID))
+}
+
+func (c *DB) company(ctx context.Context, db Querier, q string, arg string) (goovern.Company, bool, error) {
+	var comp goovern.Company
+	if err := scanCompany(db.QueryRow(ctx, q, arg), &comp); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return comp, false, nil
+		}
+		return comp, false, fmt.Errorf("failed to query company: %w", err)
+	}
+	return comp, true, nil
+}
+
+func (c *DB) LegalRepresentatives(ctx context.Context, db Querier, registrationCode string) ([]goovern.LegalRepresentative, error) {
+	q := `
+	SELECT
+		registration_code,
+		authorized_person,
+		COALESCE(role, '') AS role,
+		COALESCE(to_char(birth_date, 'YYYY-MM-DD'), '') AS birth_date,
+		COALESCE(birth_locality, '') AS birth_locality,
+		COALESCE(birth_county, '') AS birth_county,
+		COALESCE(birth_country, '') AS birth_country,
+		COALESCE(locality, '') AS locality,
+		COALESCE(county, '') AS county,
+		COALESCE(country, '') AS country
+	FROM legal_representatives
+	WHERE registration_code = $1
+	ORDER BY authorized_person
+	`
+	return collect[goovern.LegalRepresentative](ctx, db, q, registrationCode)
+}
+
+func (c *DB) FamilyBusinessRepresentatives(ctx context.Context, db Querier, registrationCode string) ([]goovern.FamilyBusinessRepresentative, error) {
+	q := `
+	SELECT
+		registration_code,
+		name,
+		COALESCE(role, '') AS role,
+		COALESCE(to_char(birth_date, 'YYYY-MM-DD'), '') AS birth_date,
+		COALESCE(birth_locality, '') AS birth_locality,
+		COALESCE(birth_county, '') AS birth_county,
+		COALESCE(birth_country, '') AS birth_country
+	FROM family_business_representatives
+	WHERE registration_code = $1
+	ORDER BY name
+	`
+	return collect[goovern.FamilyBusinessRepresentative](ctx, db, q, registrationCode)
+}
+
+// AuthorizedActivities returns the CAEN activities a company is authorized for, named from caen_codes
+func (c *DB) AuthorizedActivities(ctx context.Context, db Querier, registrationCode string) ([]goovern.AuthorizedActivity, error) {
+	q := `
+	SELECT
+		aa.registration_code,
+		aa.authorized_caen_code AS caen_code,
+		aa.caen_version,
+		COALESCE(cc.name, '') AS caen_name
+	FROM authorized_activities aa
+	LEFT JOIN LATERAL (
+		SELECT name
+		FROM caen_codes
+		WHERE class = aa.authorized_caen_code AND caen_version = aa.caen_version
+		LIMIT 1
+	) cc ON true
+	WHERE aa.registration_code = $1
+	ORDER BY aa.caen_version DESC, aa.authorized_caen_code
+	`
+	return collect[goovern.AuthorizedActivity](ctx, db, q, registrationCode)
+}
+
+// StatusHistory returns the statuses recorded for a company, named from company_statuses
+func (c *DB) StatusHistory(ctx context.Context, db Querier, registrationCode string) ([]goovern.CompanyStatus, error) {
+	q := `
+	SELECT
+		sh.registration_code,
+		sh.status_code AS code,
+		COALESCE(cs.name, '') AS name
+	FROM company_status_history sh
+	LEFT JOIN company_statuses cs ON cs.code = sh.status_code
+	WHERE sh.registration_code = $1
+	ORDER BY sh.status_code
+	`
+	return collect[goovern.CompanyStatus](ctx, db, q, registrationCode)
+}
+
+func (c *DB) ForeignBranches(ctx context.Context, db Querier, registrationCode string) ([]goovern.ForeignBranch, error) {
+	q := `
+	SELECT
+		registration_code,
+		COALESCE(unit_type, '') AS unit_type,
+		COALESCE(branch_name, '') AS branch_name,
+		COALESCE(euid, '') AS euid,
+		COALESCE(tax_code, '') AS tax_code,
+		COALESCE(country, '') AS country
+	FROM foreign_branches
+	WHERE registration_code = $1
+	ORDER BY branch_name
+	`
+	return collect[goovern.ForeignBranch](ctx, db, q, registrationCode)
+}
+
+// collect runs a query and maps every row to T by column name
+func collect[T any](ctx context.Context, db Querier, q string, args ...any) ([]T, error) {
+	rows, err := db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[T])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+	return results, nil
+}
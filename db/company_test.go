@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// argsQuerier records the arguments of QueryRow and reports no rows
+type argsQuerier struct {
+	Querier
+	args []any
+}
+
+func (q *argsQuerier) QueryRow(_ context.Context, _ string, args ...any) pgx.Row {
+	q.args = args
+	return noRow{}
+}
+
+type noRow struct{}
+
+func (noRow) Scan(...any) error { return pgx.ErrNoRows }
+
+func TestCompanyByTaxIDNormalizes(t *testing.T) {
+	c := New(slog.New(slog.DiscardHandler))
+
+	for _, input := range []string{"14399840", "RO14399840", "ro14399840", " RO 1439 9840 "} {
+		q := &argsQuerier{}
+		_, found, err := c.CompanyByTaxID(context.Background(), q, input)
+		if err != nil {
+			t.Fatalf("CompanyByTaxID(%q): %v", input, err)
+		}
+		if found {
+			t.Errorf("CompanyByTaxID(%q) found a company without rows", input)
+		}
+		if len(q.args) != 1 || q.args[0] != "14399840" {
+			t.Errorf("CompanyByTaxID(%q) queried %v, want [14399840]", input, q.args)
+		}
+	}
+}
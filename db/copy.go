@@ -0,0 +1,276 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ionut-maxim/goovern/csv"
+)
+
+// parallelCopyMinSize is the file size from which an import is copied over several connections,
+// when SetCopyParallelism allows it. BenchmarkImportStaging measures where that starts to pay off.
+const parallelCopyMinSize = 64 * humanize.MiByte
+
+// stagingPrefix starts the name of every staging table, followed by its creation time, so those
+// left behind by an import that crashed or timed out can be found and dropped
+const stagingPrefix = "import_staging_"
+
+// SetCopyParallelism sets over how many connections large files are copied. 1 copies every
+// file through the import transaction.
+func (c *DB) SetCopyParallelism(n int) {
+	c.copyParallelism = max(n, 1)
+}
+
+// parallelFile reports whether data can be copied in parallel: it must be a large enough file
+// that chunks can be read from independently
+func (c *DB) parallelFile(data io.Reader, pool Querier) (io.ReaderAt, int64, bool) {
+	if c.copyParallelism < 2 || pool == nil {
+		return nil, 0, false
+	}
+	file, ok := data.(interface {
+		io.ReaderAt
+		Stat() (os.FileInfo, error)
+	})
+	if !ok {
+		return nil, 0, false
+	}
+	info, err := file.Stat()
+	if err != nil || info.Size() < parallelCopyMinSize {
+		return nil, 0, false
+	}
+	return file, info.Size(), true
+}
+
+// stageParallel splits the rows of file into chunks at line boundaries and copies them
+// concurrently over connections of pool into an unlogged staging table, returning its name.
+// The table is created even when the copy fails, so the name is returned along with the error.
+func stageParallel(ctx context.Context, pool Querier, file io.ReaderAt, size int64, parallelism int, plan copyPlan, config ImportConfig, stats *ImportStats, progress ImportProgress, logger *slog.Logger) (string, *rejectCollector, error) {
+	// The header was already read from the start of the file
+	start, err := nextLine(file, 0, size)
+	if err != nil {
+		return "", nil, fmt.Errorf("finding end of CSV headers: %w", err)
+	}
+	bounds, err := splitLines(file, start, size, parallelism)
+	if err != nil {
+		return "", nil, fmt.Errorf("splitting CSV into chunks: %w", err)
+	}
+	chunks := len(bounds) - 1
+
+	staging := stagingTableName(config)
+	logger.Debug("Creating staging table", "staging_table", staging, "target_table", config.TableName)
+
+	// Other connections cannot see a temporary table, so the staging table is committed right away
+	createTableQuery := fmt.Sprintf(
+		`CREATE UNLOGGED TABLE %s (LIKE %s INCLUDING DEFAULTS EXCLUDING GENERATED)`,
+		pgx.Identifier{staging}.Sanitize(),
+		pgx.Identifier{config.TableName}.Sanitize(),
+	)
+	if _, err = pool.Exec(ctx, createTableQuery); err != nil {
+		return "", nil, fmt.Errorf("creating staging table: %w", err)
+	}
+
+	logger.Info("Copying data to staging table in parallel", "chunks", chunks, "size", humanize.Bytes(uint64(size)))
+	collectors := make([]*rejectCollector, chunks)
+	rows := make([]int64, chunks)
+	tracker := &chunkProgress{start: start, rows: rows, bytes: make([]int64, chunks), report: progress}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i := range chunks {
+		collectors[i] = &rejectCollector{columns: plan.columns}
+		g.Go(func() error {
+			counter := &countingReader{r: io.NewSectionReader(file, bounds[i], bounds[i+1]-bounds[i])}
+			source := plan.source(csv.NewReader(counter, '^'), collectors[i].add)
+			source.WithProgressCallback(func(rowCount int64) {
+				tracker.update(i, rowCount, counter.n)
+			}, 10000)
+
+			_, err := pool.CopyFrom(gctx, pgx.Identifier{staging}, plan.columns, source)
+			tracker.update(i, source.Rows(), counter.n)
+			if err != nil {
+				return fmt.Errorf("copying chunk at byte %d to staging table: %w", bounds[i], err)
+			}
+			return nil
+		})
+	}
+	err = g.Wait()
+	stats.RowsRead, stats.Bytes = tracker.totals()
+	if err != nil {
+		return staging, nil, err
+	}
+
+	logger.Info("Data copied to staging table", "rows", humanize.Comma(stats.RowsRead))
+	return staging, mergeRejects(collectors, rows), nil
+}
+
+// dropStaging removes the staging table of an import that failed
+func dropStaging(ctx context.Context, pool Querier, staging string, logger *slog.Logger) {
+	if err := DropStaging(ctx, pool, staging); err != nil {
+		logger.Warn("Failed to drop staging table", "staging_table", staging, "error", err)
+	}
+}
+
+// DropStaging drops an unlogged staging table if it still exists. The parallel copy commits its
+// staging table right away, so it outlives an outer transaction that rolls back.
+func DropStaging(ctx context.Context, db Querier, staging string) error {
+	if _, err := db.Exec(ctx, `DROP TABLE IF EXISTS `+pgx.Identifier{staging}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to drop staging table %s: %w", staging, err)
+	}
+	return nil
+}
+
+// DropStaleStaging drops the unlogged staging tables created more than maxAge ago that no
+// connection holds a lock on, left behind by imports that crashed or timed out, and returns how
+// many it dropped. maxAge must exceed the longest import: a parallel copy holds no lock on its
+// table between creating it and copying into it, nor between the copy and the merge.
+func (c *DB) DropStaleStaging(ctx context.Context, db Querier, maxAge time.Duration) (int, error) {
+	q := `
+	SELECT c.relname FROM pg_class c
+	WHERE c.relkind = 'r'
+		AND c.relpersistence = 'u'
+		AND pg_table_is_visible(c.oid)
+		AND starts_with(c.relname, $1)
+		AND NOT EXISTS (SELECT 1 FROM pg_locks l WHERE l.relation = c.oid)
+	`
+	rows, err := db.Query(ctx, q, stagingPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query stale staging tables: %w", err)
+	}
+	unlocked, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("failed to query stale staging tables: %w", err)
+	}
+
+	dropped := 0
+	for _, staging := range unlocked {
+		created, ok := stagingCreatedAt(staging)
+		if !ok || time.Since(created) < maxAge {
+			continue
+		}
+		if err = DropStaging(ctx, db, staging); err != nil {
+			return dropped, err
+		}
+		dropped++
+		c.logger.Info("Dropped stale staging table", "staging_table", staging, "created_at", created)
+	}
+	return dropped, nil
+}
+
+// stagingCreatedAt reads the creation time out of the name of a staging table
+func stagingCreatedAt(staging string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(staging, stagingPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	encoded, _, ok := strings.Cut(rest, "_")
+	if !ok {
+		return time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(encoded, 36, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
+
+// mergeRejects combines the rejects of all chunks, turning their line numbers into lines of the
+// whole file. rows holds the number of rows of every chunk.
+func mergeRejects(collectors []*rejectCollector, rows []int64) *rejectCollector {
+	merged := &rejectCollector{}
+	var offset int64
+	for i, c := range collectors {
+		merged.columns = c.columns
+		merged.count += c.count
+		merged.rows += c.rows
+		for _, r := range c.rejects {
+			if len(merged.rejects) == maxRejects {
+				break
+			}
+			r.line += offset
+			merged.rejects = append(merged.rejects, r)
+		}
+		offset += rows[i]
+	}
+	return merged
+}
+
+// chunkProgress sums the progress of chunks copied concurrently
+type chunkProgress struct {
+	mu     sync.Mutex
+	start  int64 // Bytes before the first chunk
+	rows   []int64
+	bytes  []int64
+	report ImportProgress
+}
+
+func (p *chunkProgress) update(chunk int, rows, bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rows[chunk] = rows
+	p.bytes[chunk] = bytes
+	if p.report != nil {
+		p.report(p.sum())
+	}
+}
+
+func (p *chunkProgress) totals() (rows, bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sum()
+}
+
+func (p *chunkProgress) sum() (rows, bytes int64) {
+	bytes = p.start
+	for i := range p.rows {
+		rows += p.rows[i]
+		bytes += p.bytes[i]
+	}
+	return rows, bytes
+}
+
+// splitLines cuts [start, size) of r into at most n ranges that end at line boundaries,
+// returning their boundaries
+func splitLines(r io.ReaderAt, start, size int64, n int) ([]int64, error) {
+	bounds := []int64{start}
+	for i := 1; i < n; i++ {
+		next, err := nextLine(r, start+(size-start)*int64(i)/int64(n), size)
+		if err != nil {
+			return nil, err
+		}
+		// Very long lines can swallow a whole chunk
+		if next > bounds[len(bounds)-1] && next < size {
+			bounds = append(bounds, next)
+		}
+	}
+	return append(bounds, size), nil
+}
+
+// nextLine returns the offset just past the first newline at or after off, or size if there is none
+func nextLine(r io.ReaderAt, off, size int64) (int64, error) {
+	buf := make([]byte, 64*humanize.KiByte)
+	for off < size {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), size-off)], off)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return off + int64(i) + 1, nil
+		}
+		off += int64(n)
+		if err == io.EOF || n == 0 {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
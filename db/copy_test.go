@@ -0,0 +1,199 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ionut-maxim/goovern/csv"
+)
+
+func TestSplitLines(t *testing.T) {
+	long := strings.Repeat("x", 100000) // Longer than the buffer nextLine reads with
+
+	tests := []struct {
+		name  string
+		data  string
+		start int64
+		n     int
+		want  []int64
+	}{
+		{name: "no trailing newline", data: "aa\nbb\ncc", n: 3, want: []int64{0, 3, 6, 8}},
+		{name: "after header", data: "h\naa\nbb\n", start: 2, n: 3, want: []int64{2, 5, 8}},
+		{name: "line longer than a chunk", data: "a\n" + strings.Repeat("x", 20) + "\nb\n", n: 4, want: []int64{0, 23, 25}},
+		{name: "line longer than the read buffer", data: "a\n" + long + "\nb\n", n: 2, want: []int64{0, 100003, 100005}},
+		{name: "more chunks than lines", data: "a\nb\n", n: 10, want: []int64{0, 2, 4}},
+		{name: "no lines", data: "h\n", start: 2, n: 4, want: []int64{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitLines(strings.NewReader(tt.data), tt.start, int64(len(tt.data)), tt.n)
+			if err != nil {
+				t.Fatalf("splitLines: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitLines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextLine(t *testing.T) {
+	long := "a\n" + strings.Repeat("x", 100000) + "\nb"
+
+	tests := []struct {
+		name string
+		data string
+		off  int64
+		want int64
+	}{
+		{name: "at newline", data: "ab\ncd\n", off: 2, want: 3},
+		{name: "mid line", data: "ab\ncd\n", off: 3, want: 6},
+		{name: "no trailing newline", data: "ab\ncd", off: 3, want: 5},
+		{name: "at end", data: "ab\n", off: 3, want: 3},
+		{name: "line longer than the read buffer", data: long, off: 2, want: 100003},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextLine(strings.NewReader(tt.data), tt.off, int64(len(tt.data)))
+			if err != nil {
+				t.Fatalf("nextLine: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("nextLine = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkImportStaging compares staging a CSV through the import transaction with copying it
+// over several connections, for files below and above parallelCopyMinSize. It needs a database,
+// given as GOO_TEST_DB_URL:
+//
+//	GOO_TEST_DB_URL=postgres://... go test -run '^$' -bench ImportStaging ./db
+func BenchmarkImportStaging(b *testing.B) {
+	url := os.Getenv("GOO_TEST_DB_URL")
+	if url == "" {
+		b.Skip("GOO_TEST_DB_URL is not set")
+	}
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer pool.Close()
+
+	if _, err = pool.Exec(ctx, `CREATE TABLE bench_import (id bigint, name text, county text)`); err != nil {
+		b.Fatal(err)
+	}
+	defer pool.Exec(ctx, `DROP TABLE bench_import`)
+
+	plan := copyPlan{width: 3, fields: []int{0, 1, 2}, columns: []string{"id", "name", "county"}}
+	config := ImportConfig{TableName: "bench_import", TempTableName: "bench_import"}
+	logger := slog.New(slog.DiscardHandler)
+
+	// About 3 MiB, 35 MiB and 140 MiB
+	for _, rows := range []int{100_000, 1_000_000, 4_000_000} {
+		path := writeBenchCSV(b, rows)
+		info, err := os.Stat(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("rows=%d/serial", rows), func(b *testing.B) {
+			b.SetBytes(info.Size())
+			for b.Loop() {
+				f, err := os.Open(path)
+				if err != nil {
+					b.Fatal(err)
+				}
+				reader := csv.NewReader(f, '^')
+				if _, err = reader.Read(); err != nil {
+					b.Fatal(err)
+				}
+				tx, err := pool.Begin(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var stats ImportStats
+				_, _, err = stageSerial(ctx, tx, reader, plan, config, false, &stats, func(int64) {}, logger)
+				tx.Rollback(ctx)
+				f.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		for _, parallelism := range []int{2, 4, 8} {
+			b.Run(fmt.Sprintf("rows=%d/parallel-%d", rows, parallelism), func(b *testing.B) {
+				b.SetBytes(info.Size())
+				for b.Loop() {
+					f, err := os.Open(path)
+					if err != nil {
+						b.Fatal(err)
+					}
+					var stats ImportStats
+					staging, _, err := stageParallel(ctx, pool, f, info.Size(), parallelism, plan, config, &stats, nil, logger)
+					if staging != "" {
+						DropStaging(ctx, pool, staging)
+					}
+					f.Close()
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// writeBenchCSV writes a CSV of the given number of company rows and returns its path
+func writeBenchCSV(b *testing.B, rows int) string {
+	path := filepath.Join(b.TempDir(), "bench.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "id^name^county")
+	for i := range rows {
+		fmt.Fprintf(w, "%d^COMPANIA %d SRL^Bucuresti\n", i, i)
+	}
+	if err = w.Flush(); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+func TestStagingCreatedAt(t *testing.T) {
+	before := time.Now().Truncate(time.Second)
+	staging := stagingTableName(ImportConfig{TempTableName: "companies_import"})
+	created, ok := stagingCreatedAt(staging)
+	if !ok {
+		t.Fatalf("stagingCreatedAt(%q) found no creation time", staging)
+	}
+	if created.Before(before) || created.After(time.Now()) {
+		t.Errorf("stagingCreatedAt(%q) = %s, want about %s", staging, created, before)
+	}
+
+	for _, name := range []string{"companies_import_abc", "import_staging_", "import_staging_zz!_companies_x"} {
+		if _, ok := stagingCreatedAt(name); ok {
+			t.Errorf("stagingCreatedAt(%q) found a creation time", name)
+		}
+	}
+}
@@ -22,12 +22,21 @@ type Querier interface {
 }
 
 type DB struct {
-	logger *slog.Logger
+	logger          *slog.Logger
+	imports         map[string]ImportConfig
+	copyParallelism int
+	bulkLoad        bool
+	fullReload      bool
 }
 
 func New(logger *slog.Logger) *DB {
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
-	return &DB{logger: logger.With("component", "db")}
+	imports, err := ParseImportConfigs(defaultImportConfigs)
+	if err != nil {
+		// The embedded configs are fixed at build time
+		panic(err)
+	}
+	return &DB{logger: logger.With("component", "db"), imports: imports, copyParallelism: 1}
 }
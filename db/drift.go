@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ionut-maxim/goovern/metrics"
+)
+
+// SchemaDriftError reports CSV headers that do not line up with the columns of the target table
+type SchemaDriftError struct {
+	Resource string
+	Table    string
+	Unknown  []string // Headers with no column in the table
+	Missing  []string // Columns without a default that no header fills
+}
+
+func (e *SchemaDriftError) Error() string {
+	var parts []string
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "unknown columns: "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing columns: "+strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("schema drift between %s and table %s (%s)", e.Resource, e.Table, strings.Join(parts, "; "))
+}
+
+// tableColumn is a writable column of an import target table
+type tableColumn struct {
+	Name       string `db:"column_name"`
+	NotNull    bool   `db:"not_null"`
+	HasDefault bool   `db:"has_default"`
+}
+
+func tableColumns(ctx context.Context, db Querier, table string) ([]tableColumn, error) {
+	q := `
+	SELECT column_name, is_nullable = 'NO' AS not_null, column_default IS NOT NULL AS has_default
+	FROM information_schema.columns
+	WHERE table_schema = current_schema() AND table_name = $1 AND is_generated = 'NEVER'
+	ORDER BY ordinal_position
+	`
+	return collect[tableColumn](ctx, db, q, table)
+}
+
+// detectDrift compares normalized headers with the table columns. Missing columns only fail
+// the import when they are NOT NULL; nullable ones are reported and left NULL.
+func detectDrift(resource, table string, headers []string, columns []tableColumn) (drift *SchemaDriftError, fatal bool) {
+	d := SchemaDriftError{Resource: resource, Table: table}
+	for _, header := range headers {
+		if !slices.ContainsFunc(columns, func(c tableColumn) bool { return c.Name == header }) {
+			d.Unknown = append(d.Unknown, header)
+		}
+	}
+	for _, column := range columns {
+		if column.HasDefault || slices.Contains(headers, column.Name) {
+			continue
+		}
+		d.Missing = append(d.Missing, column.Name)
+		fatal = fatal || column.NotNull
+	}
+
+	if len(d.Unknown) == 0 && len(d.Missing) == 0 {
+		return nil, false
+	}
+	return &d, fatal
+}
+
+// RecordSchemaDrift stores a drift event, noting whether the import went ahead regardless
+func (c *DB) RecordSchemaDrift(ctx context.Context, db Querier, drift *SchemaDriftError, imported bool) error {
+	metrics.SchemaDrift.WithLabelValues(drift.Resource).Inc()
+
+	q := `
+	INSERT INTO import_schema_drift (resource, table_name, unknown_columns, missing_columns, imported)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+	unknown, missing := drift.Unknown, drift.Missing
+	if unknown == nil {
+		unknown = []string{}
+	}
+	if missing == nil {
+		missing = []string{}
+	}
+	if _, err := db.Exec(ctx, q, drift.Resource, drift.Table, unknown, missing, imported); err != nil {
+		return fmt.Errorf("failed to record schema drift: %w", err)
+	}
+	return nil
+}
+
+// knownFields returns the positions of the headers that are columns of the table
+func knownFields(headers []string, columns []tableColumn) []int {
+	var fields []int
+	for i, header := range headers {
+		if slices.ContainsFunc(columns, func(c tableColumn) bool { return c.Name == header }) {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
@@ -6,7 +6,9 @@ import (
 	"io"
 	"log/slog"
 	"math/rand/v2"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/jackc/pgx/v5"
@@ -15,114 +17,272 @@ import (
 	"github.com/ionut-maxim/goovern/csv"
 )
 
-// Import requires a transactional client to work properly because we are using a `TEMP` table
-func (c *DB) Import(ctx context.Context, db Tx, resource ckan.Resource, data io.Reader) error {
+// Import requires a transactional client to work properly because we are using a `TEMP` table.
+// Files of at least parallelCopyMinSize are instead copied over several connections of pool into
+// an unlogged staging table, when the copy parallelism allows it. Bulk loads of empty tables
+// stage into an unlogged table too and drop the secondary indexes of the target. A reload
+// truncates the target after staging and then bulk loads it.
+// The stats are filled in as far as the import got, also when it fails. progress may be nil.
+func (c *DB) Import(ctx context.Context, db Tx, pool Querier, resource ckan.Resource, data io.Reader, reload bool, progress ImportProgress) (stats ImportStats, err error) {
 	logger := c.logger.With("resource_name", resource.Name, "table", resource.Name)
 
-	config, ok := importConfigs[resource.Name]
+	config, ok := c.imports[resource.Name]
 	if !ok {
 		err := fmt.Errorf("no import configuration registered for resource: %s", resource.Name)
 		logger.Error("Import configuration not found", "error", err)
-		return err
+		return stats, err
 	}
 
+	counter := &countingReader{r: data}
+
 	logger.Debug("Reading CSV headers")
-	reader := csv.NewReader(data, '^')
+	reader := csv.NewReader(counter, '^')
 
 	headers, err := reader.Read()
 	if err != nil {
 		logger.Error("Failed to read CSV headers", "error", err)
-		return fmt.Errorf("reading CSV headers: %w", err)
+		return stats, fmt.Errorf("reading CSV headers: %w", err)
 	}
 
 	stripBOM(headers)
+	normalizeHeaders(headers, config.ColumnMapping)
 	logger.Debug("CSV headers parsed", "column_count", len(headers))
 
-	source := csv.NewSource(reader)
+	columns, err := tableColumns(ctx, db, config.TableName)
+	if err != nil {
+		logger.Error("Failed to read target table columns", "error", err)
+		return stats, fmt.Errorf("reading target columns: %w", err)
+	}
+
+	// Unknown headers are dropped from the copy when the config allows it
+	fields := knownFields(headers, columns)
+	if drift, fatal := detectDrift(resource.Name, config.TableName, headers, columns); drift != nil {
+		if fatal || (len(drift.Unknown) > 0 && !config.IgnoreUnknownColumns) {
+			logger.Error("CSV headers do not match the target table", "unknown", drift.Unknown, "missing", drift.Missing)
+			return stats, drift
+		}
+		logger.Warn("CSV headers drifted from the target table, importing known columns",
+			"unknown", drift.Unknown, "missing", drift.Missing)
+		if err = c.RecordSchemaDrift(ctx, db, drift, true); err != nil {
+			return stats, err
+		}
+	}
+
+	plan := copyPlan{width: len(headers), fields: fields, columns: make([]string, len(fields))}
+	for i, f := range fields {
+		plan.columns[i] = headers[f]
+	}
+	plan.transforms = columnTransforms(plan.columns, config.Transforms, columns)
 
 	tx, err := db.Begin(ctx)
 	if err != nil {
 		logger.Error("Failed to begin transaction", "error", err)
-		return fmt.Errorf("beginning transaction: %w", err)
+		return stats, fmt.Errorf("beginning transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	logger.Info("Starting data import to database")
-	bytes, rowsAffected, err := importWithConfig(ctx, tx, headers, source, config, logger)
+	// A reload empties the table, so it is bulk loaded like an initial load
+	bulk := reload && c.bulkLoad
+	if !reload {
+		if bulk, err = c.bulkLoadable(ctx, tx, config.TableName); err != nil {
+			logger.Error("Failed to check for an initial load", "error", err)
+			return stats, err
+		}
+	}
+
+	logger.Info("Starting data import to database", "bulk_load", bulk, "reload", reload)
+	var (
+		staging string
+		rejects *rejectCollector
+	)
+	file, size, parallel := c.parallelFile(data, pool)
+	if parallel {
+		staging, rejects, err = stageParallel(ctx, pool, file, size, c.copyParallelism, plan, config, &stats, progress, logger)
+		stats.StagingTable = staging
+		if staging != "" {
+			// The staging table is a regular table; dropping it waits for the savepoint to release it
+			defer func() {
+				if err != nil {
+					tx.Rollback(ctx)
+					dropStaging(context.WithoutCancel(ctx), pool, staging, logger)
+				}
+			}()
+		}
+	} else {
+		staging, rejects, err = stageSerial(ctx, tx, reader, plan, config, bulk, &stats, func(rows int64) {
+			if progress != nil {
+				progress(rows, counter.n)
+			}
+		}, logger)
+		stats.Bytes = counter.n
+	}
+	// Truncated and dropped only now: the parallel copy reads the target table's definition from
+	// other connections
+	if err == nil && reload {
+		err = truncateForReload(ctx, tx, config.TableName, logger)
+	}
+	if err == nil && bulk {
+		stats.IndexesDeferred, err = deferIndexes(ctx, tx, config.TableName, logger)
+	}
+	if err == nil {
+		err = merge(ctx, tx, resource.Name, staging, parallel || bulk, plan.columns, config, rejects, &stats, logger)
+	}
 	if err != nil {
 		logger.Error("Import failed", "error", err)
-		return err
+		return stats, err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error("Failed to commit transaction", "error", err)
-		return fmt.Errorf("committing transaction: %w", err)
+		return stats, fmt.Errorf("committing transaction: %w", err)
 	}
 
 	logger.Info("Import completed successfully",
-		"rows_inserted", rowsAffected,
-		"bytes_processed", humanize.Bytes(uint64(bytes)))
+		"rows_read", stats.RowsRead,
+		"rows_inserted", stats.RowsInserted,
+		"rows_rejected", stats.RowsRejected,
+		"bytes_processed", humanize.Bytes(uint64(stats.Bytes)))
 
-	return nil
+	return stats, nil
 }
 
-func importWithConfig(ctx context.Context, tx Tx, headers []string, source *csv.Source, config ImportConfig, logger *slog.Logger) (bytes int64, rows int64, err error) {
-	normalizeHeaders(headers, config.ColumnMapping)
+// copyPlan describes how CSV rows are copied into a staging table
+type copyPlan struct {
+	width      int      // Fields every CSV row has
+	fields     []int    // Positions of the copied fields
+	columns    []string // Columns the copied fields go to, in order
+	transforms []Transform
+}
 
-	tempTable := fmt.Sprintf("%s_%d", config.TempTableName, rand.IntN(5000))
-	logger.Debug("Creating temporary table", "temp_table", tempTable, "target_table", config.TableName)
+func (p copyPlan) source(reader *csv.GoovernReader, onReject csv.RejectCallback) *csv.Source {
+	return csv.NewSource(reader).WithFields(p.width, p.fields).WithTransforms(p.transforms, onReject)
+}
+
+// stagingTableName picks a name for the staging table of one import, starting with its creation
+// time for DropStaleStaging. Unlogged staging tables are shared by all connections, so the
+// suffix is random enough not to collide.
+func stagingTableName(config ImportConfig) string {
+	return fmt.Sprintf("%s%s_%s_%s", stagingPrefix, strconv.FormatInt(time.Now().Unix(), 36),
+		config.TempTableName, strconv.FormatUint(rand.Uint64(), 36))
+}
+
+// stageSerial streams the rest of reader into a staging table of tx and returns its name. The
+// table is temporary, or unlogged for bulk loads: temporary tables live in the small per-session
+// buffers, which an initial load overflows.
+func stageSerial(ctx context.Context, tx Tx, reader *csv.GoovernReader, plan copyPlan, config ImportConfig, unlogged bool, stats *ImportStats, progress csv.ProgressCallback, logger *slog.Logger) (string, *rejectCollector, error) {
+	tempTable := stagingTableName(config)
+	logger.Debug("Creating staging table", "temp_table", tempTable, "target_table", config.TableName, "unlogged", unlogged)
 
 	createTableQuery := fmt.Sprintf(
 		`CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS EXCLUDING GENERATED) ON COMMIT DROP`,
 		pgx.Identifier{tempTable}.Sanitize(),
 		pgx.Identifier{config.TableName}.Sanitize(),
 	)
-	if _, err = tx.Exec(ctx, createTableQuery); err != nil {
-		return 0, 0, fmt.Errorf("creating temp table: %w", err)
+	if unlogged {
+		// Created in the transaction, so a failed import rolls it back
+		createTableQuery = fmt.Sprintf(
+			`CREATE UNLOGGED TABLE %s (LIKE %s INCLUDING DEFAULTS EXCLUDING GENERATED)`,
+			pgx.Identifier{tempTable}.Sanitize(),
+			pgx.Identifier{config.TableName}.Sanitize(),
+		)
+	}
+	if _, err := tx.Exec(ctx, createTableQuery); err != nil {
+		return "", nil, fmt.Errorf("creating temp table: %w", err)
 	}
 
-	// Add progress callback to log every 10,000 rows
+	logger.Debug("Copying data to temporary table")
+	rejects := &rejectCollector{columns: plan.columns}
+	source := plan.source(reader, rejects.add)
+	// Log progress every 10,000 rows
 	source.WithProgressCallback(func(rowCount int64) {
 		logger.Debug("Import progress", "rows_processed", humanize.Comma(rowCount))
+		progress(rowCount)
 	}, 10000)
 
-	logger.Debug("Copying data to temporary table")
-	bytes, err = tx.CopyFrom(
+	copied, err := tx.CopyFrom(
 		ctx,
 		pgx.Identifier{tempTable},
-		headers,
+		plan.columns,
 		source,
 	)
+	stats.RowsRead = source.Rows()
 	if err != nil {
-		return 0, 0, fmt.Errorf("copying to temp table: %w", err)
+		return "", nil, fmt.Errorf("copying to temp table: %w", err)
 	}
 
-	logger.Info("Data copied to temporary table", "bytes", humanize.Bytes(uint64(bytes)))
+	logger.Info("Data copied to temporary table", "rows", humanize.Comma(copied))
+	return tempTable, rejects, nil
+}
+
+// merge records the rejects of the staged rows and inserts those not imported yet into the
+// target table. An unlogged staging table is dropped with the transaction when drop is set.
+func merge(ctx context.Context, tx Tx, resourceName, staging string, drop bool, columns []string, config ImportConfig, rejects *rejectCollector, stats *ImportStats, logger *slog.Logger) error {
+	stats.RowsRejected = rejects.rows
+	if rejects.count > 0 {
+		first := rejects.rejects[0]
+		logger.Warn("Rejected unparseable values",
+			"rejected", rejects.count,
+			"first_line", first.line,
+			"first_column", first.column,
+			"first_error", first.err)
+		if err := saveRejects(ctx, tx, resourceName, rejects.rejects); err != nil {
+			return err
+		}
+	}
 
 	logger.Debug("Inserting data into target table", "target_table", config.TableName)
-	// Build column list from headers (which are the non-generated columns in temp table)
+	// Build column list from the copied columns (which are the non-generated columns in the staging table)
 	columnList := ""
-	for i, header := range headers {
+	for i, column := range columns {
 		if i > 0 {
 			columnList += ", "
 		}
-		columnList += pgx.Identifier{header}.Sanitize()
+		columnList += pgx.Identifier{column}.Sanitize()
+	}
+
+	conflictTarget := ""
+	if len(config.ConflictKeys) > 0 {
+		keys := make([]string, len(config.ConflictKeys))
+		for i, key := range config.ConflictKeys {
+			keys[i] = pgx.Identifier{key}.Sanitize()
+		}
+		conflictTarget = "(" + strings.Join(keys, ", ") + ") "
 	}
 
 	insertQuery := fmt.Sprintf(
-		`INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT DO NOTHING`,
+		`INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT %sDO NOTHING`,
 		pgx.Identifier{config.TableName}.Sanitize(),
 		columnList,
 		columnList,
-		pgx.Identifier{tempTable}.Sanitize(),
+		pgx.Identifier{staging}.Sanitize(),
+		conflictTarget,
 	)
 	result, err := tx.Exec(ctx, insertQuery)
 	if err != nil {
-		return 0, 0, fmt.Errorf("inserting from temp table: %w", err)
+		return fmt.Errorf("inserting from staging table: %w", err)
 	}
 
 	logger.Debug("Data inserted", "rows_affected", result.RowsAffected())
-	return bytes, result.RowsAffected(), nil
+	stats.RowsInserted = result.RowsAffected()
+
+	if drop {
+		if _, err = tx.Exec(ctx, `DROP TABLE `+pgx.Identifier{staging}.Sanitize()); err != nil {
+			return fmt.Errorf("dropping staging table: %w", err)
+		}
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
 
 // stripBOM removes the UTF-8 BOM from the first header if present
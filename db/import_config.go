@@ -1,123 +1,259 @@
 package db
 
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/yaml.v3"
+)
+
 type ImportConfig struct {
-	TableName     string            // Destination table name
-	TempTableName string            // Temp table prefix (will have random number appended)
-	ColumnMapping map[string]string // CSV header -> DB column mapping
+	TableName     string               // Destination table name
+	TempTableName string               // Staging table prefix (will have a random suffix appended)
+	ColumnMapping map[string]string    // CSV header -> DB column mapping
+	Transforms    map[string]Transform // DB column -> transform, applied after trimming and turning empty values into NULL
+	ConflictKeys  []string             // Columns of a unique index identifying rows already imported
+	DependsOn     []string             // Resources that must be imported before this one
+
+	IgnoreUnknownColumns bool // Import CSVs with headers the table has no column for, skipping those fields
+}
+
+//go:embed imports.yaml
+var defaultImportConfigs []byte
+
+// transformNames are the transforms an import config file can refer to
+var transformNames = map[string]Transform{
+	"trim":          Trim,
+	"null_if_empty": NullIfEmpty,
+	"upper":         Upper,
+	"int":           ParseInt,
+	"date":          ParseDate,
+	"cui":           NormalizeCUI,
+}
+
+type importConfigFile struct {
+	Resources map[string]struct {
+		Table         string              `yaml:"table"`
+		TempTable     string              `yaml:"temp_table"`
+		Columns       map[string]string   `yaml:"columns"`
+		Transforms    map[string][]string `yaml:"transforms"`
+		ConflictKeys  []string            `yaml:"conflict_keys"`
+		DependsOn     []string            `yaml:"depends_on"`
+		IgnoreUnknown bool                `yaml:"ignore_unknown_columns"`
+	} `yaml:"resources"`
+}
+
+// ParseImportConfigs reads import configurations keyed by resource name from YAML,
+// checking transform names and dependencies but not the database schema
+func ParseImportConfigs(data []byte) (map[string]ImportConfig, error) {
+	var file importConfigFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse import configs: %w", err)
+	}
+	if len(file.Resources) == 0 {
+		return nil, errors.New("no resources configured")
+	}
+
+	configs := make(map[string]ImportConfig, len(file.Resources))
+	for name, r := range file.Resources {
+		if r.Table == "" {
+			return nil, fmt.Errorf("resource %s: table is required", name)
+		}
+
+		config := ImportConfig{
+			TableName:     r.Table,
+			TempTableName: r.TempTable,
+			ColumnMapping: make(map[string]string, len(r.Columns)),
+			Transforms:    make(map[string]Transform, len(r.Transforms)),
+			ConflictKeys:  r.ConflictKeys,
+			DependsOn:     r.DependsOn,
+
+			IgnoreUnknownColumns: r.IgnoreUnknown,
+		}
+		if config.TempTableName == "" {
+			config.TempTableName = r.Table
+		}
+		for header, column := range r.Columns {
+			config.ColumnMapping[strings.ToLower(header)] = column
+		}
+
+		for column, names := range r.Transforms {
+			chain := make([]Transform, len(names))
+			for i, n := range names {
+				t, ok := transformNames[n]
+				if !ok {
+					return nil, fmt.Errorf("resource %s: unknown transform %q for column %s", name, n, column)
+				}
+				chain[i] = t
+			}
+			config.Transforms[column] = Chain(chain...)
+		}
+
+		for _, dep := range r.DependsOn {
+			if _, ok := file.Resources[dep]; !ok {
+				return nil, fmt.Errorf("resource %s: depends on unknown resource %s", name, dep)
+			}
+		}
+
+		configs[name] = config
+	}
+
+	if _, err := importTiers(configs, nil); err != nil {
+		return nil, err
+	}
+	return configs, nil
 }
 
-var importConfigs = map[string]ImportConfig{
-	"N_VERSIUNE_CAEN.CSV": {
-		TableName:     "caen_versions",
-		TempTableName: "caen_versions",
-		ColumnMapping: map[string]string{
-			"cod":       "code",
-			"descriere": "description",
-		},
-	},
-	"N_CAEN.CSV": {
-		TableName:     "caen_codes",
-		TempTableName: "caen_codes",
-		ColumnMapping: map[string]string{
-			"sectiunea":     "section",
-			"subsectiunea":  "subsection",
-			"diviziunea":    "division",
-			"grupa":         "group",
-			"clasa":         "class",
-			"denumire":      "name",
-			"versiune_caen": "caen_version",
-		},
-	},
-	"N_STARE_FIRMA.CSV": {
-		TableName:     "company_statuses",
-		TempTableName: "company_statuses",
-		ColumnMapping: map[string]string{
-			"cod":      "code",
-			"denumire": "name",
-		},
-	},
-	"OD_FIRME.CSV": {
-		TableName:     "companies",
-		TempTableName: "companies",
-		ColumnMapping: map[string]string{
-			"denumire":           "name",
-			"cui":                "tax_id",
-			"cod_inmatriculare":  "registration_code",
-			"data_inmatriculare": "registration_date",
-			"euid":               "euid",
-			"forma_juridica":     "legal_form",
-			"adr_tara":           "country",
-			"adr_judet":          "county",
-			"adr_localitate":     "locality",
-			"adr_den_strada":     "street_name",
-			"adr_nr_strada":      "street_number",
-			"adr_bloc":           "building",
-			"adr_scara":          "staircase",
-			"adr_etaj":           "floor",
-			"adr_apartament":     "apartment",
-			"adr_cod_postal":     "postal_code",
-			"adr_sector":         "sector",
-			"adr_completare":     "address_details",
-			"web":                "website",
-			"tara_firma_mama":    "parent_company_country",
-		},
-	},
-	"OD_CAEN_AUTORIZAT.CSV": {
-		TableName:     "authorized_activities",
-		TempTableName: "authorized_activities",
-		ColumnMapping: map[string]string{
-			"cod_inmatriculare":  "registration_code",
-			"cod_caen_autorizat": "authorized_caen_code",
-			"ver_caen_autorizat": "caen_version",
-		},
-	},
-	"OD_STARE_FIRMA.CSV": {
-		TableName:     "company_status_history",
-		TempTableName: "company_status_history",
-		ColumnMapping: map[string]string{
-			"cod_inmatriculare": "registration_code",
-			"cod":               "status_code",
-		},
-	},
-	"OD_REPREZENTANTI_LEGALI.CSV": {
-		TableName:     "legal_representatives",
-		TempTableName: "legal_representatives",
-		ColumnMapping: map[string]string{
-			"cod_inmatriculare":      "registration_code",
-			"persoana_imputernicita": "authorized_person",
-			"calitate":               "role",
-			"data_nastere":           "birth_date",
-			"localitate_nastere":     "birth_locality",
-			"judet_nastere":          "birth_county",
-			"tara_nastere":           "birth_country",
-			"localitate":             "locality",
-			"judet":                  "county",
-			"tara":                   "country",
-		},
-	},
-	"OD_REPREZENTANTI_IF.CSV": {
-		TableName:     "family_business_representatives",
-		TempTableName: "family_business_representatives",
-		ColumnMapping: map[string]string{
-			"cod_inmatriculare":  "registration_code",
-			"nume":               "name",
-			"data_nastere":       "birth_date",
-			"localitate_nastere": "birth_locality",
-			"judet_nastere":      "birth_county",
-			"tara_nastere":       "birth_country",
-			"calitate":           "role",
-		},
-	},
-	"OD_SUCURSALE_ALTE_STATE_MEMBRE.CSV": {
-		TableName:     "foreign_branches",
-		TempTableName: "foreign_branches",
-		ColumnMapping: map[string]string{
-			"cod_inmatriculare":  "registration_code",
-			"tip_unitate":        "unit_type",
-			"denumire_sucursala": "branch_name",
-			"euid":               "euid",
-			"cod_fiscal":         "tax_code",
-			"tara":               "country",
-		},
-	},
+// LoadImportConfigs replaces the embedded import configurations with the ones in a YAML file
+func (c *DB) LoadImportConfigs(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read import configs: %w", err)
+	}
+	configs, err := ParseImportConfigs(data)
+	if err != nil {
+		return err
+	}
+	c.imports = configs
+	c.logger.Info("Loaded import configs", "path", path, "resources", len(configs))
+	return nil
+}
+
+// ValidateImportConfigs checks every import configuration against the live schema: the table
+// must exist, mapped and transformed columns must be writable and conflict keys must match a unique index
+func (c *DB) ValidateImportConfigs(ctx context.Context, db Querier) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(c.imports)) {
+		if err := validateImportConfig(ctx, db, c.imports[name]); err != nil {
+			errs = append(errs, fmt.Errorf("resource %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateImportConfig(ctx context.Context, db Querier, config ImportConfig) error {
+	columns, err := tableColumns(ctx, db, config.TableName)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("table %s does not exist", config.TableName)
+	}
+
+	var errs []error
+	check := func(kind, column string) {
+		if !slices.ContainsFunc(columns, func(c tableColumn) bool { return c.Name == column }) {
+			errs = append(errs, fmt.Errorf("%s column %s is not a writable column of %s", kind, column, config.TableName))
+		}
+	}
+	for _, column := range config.ColumnMapping {
+		check("mapped", column)
+	}
+	for column := range config.Transforms {
+		check("transformed", column)
+	}
+	for _, column := range config.ConflictKeys {
+		check("conflict key", column)
+	}
+
+	if len(config.ConflictKeys) > 0 {
+		q := `
+		SELECT array_agg(a.attname::text ORDER BY a.attname)
+		FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = to_regclass($1) AND i.indisunique AND i.indpred IS NULL
+		GROUP BY i.indexrelid
+		`
+		rows, err := db.Query(ctx, q, config.TableName)
+		if err != nil {
+			return fmt.Errorf("failed to query unique indexes: %w", err)
+		}
+		indexes, err := pgx.CollectRows(rows, pgx.RowTo[[]string])
+		if err != nil {
+			return fmt.Errorf("failed to collect unique indexes: %w", err)
+		}
+
+		keys := slices.Clone(config.ConflictKeys)
+		sort.Strings(keys)
+		if !slices.ContainsFunc(indexes, func(index []string) bool { return slices.Equal(index, keys) }) {
+			errs = append(errs, fmt.Errorf("conflict keys %v do not match a unique index of %s", config.ConflictKeys, config.TableName))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ImportTiers groups resources into tiers that can be imported in parallel, each tier
+// depending only on earlier ones. Resources without an import config go last.
+func (c *DB) ImportTiers(names []string) [][]string {
+	tiers, _ := importTiers(c.imports, names)
+	return tiers
+}
+
+// importTiers orders the given resources, or all configured ones when names is nil,
+// by the length of their longest dependency chain
+func importTiers(configs map[string]ImportConfig, names []string) ([][]string, error) {
+	depth := make(map[string]int, len(configs))
+	visiting := make(map[string]bool)
+
+	var visit func(name string) (int, error)
+	visit = func(name string) (int, error) {
+		if d, ok := depth[name]; ok {
+			return d, nil
+		}
+		if visiting[name] {
+			return 0, fmt.Errorf("import dependency cycle through %s", name)
+		}
+		visiting[name] = true
+		d := 0
+		for _, dep := range configs[name].DependsOn {
+			dd, err := visit(dep)
+			if err != nil {
+				return 0, err
+			}
+			d = max(d, dd+1)
+		}
+		visiting[name] = false
+		depth[name] = d
+		return d, nil
+	}
+
+	if names == nil {
+		for name := range configs {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var tiers [][]string
+	var unknown []string
+	for _, name := range names {
+		if _, ok := configs[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		d, err := visit(name)
+		if err != nil {
+			return nil, err
+		}
+		for len(tiers) <= d {
+			tiers = append(tiers, nil)
+		}
+		tiers[d] = append(tiers[d], name)
+	}
+
+	// Tiers of dependencies absent from names are left empty
+	tiers = slices.DeleteFunc(tiers, func(tier []string) bool { return len(tier) == 0 })
+	if len(unknown) > 0 {
+		tiers = append(tiers, unknown)
+	}
+	return tiers, nil
 }
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/ckan"
+)
+
+// ImportStats counts what an import read and changed
+type ImportStats struct {
+	RowsRead     int64
+	RowsInserted int64 // Imports only add rows: those already imported are skipped, never updated or deleted
+	RowsRejected int64 // Rows with at least one rejected value, imported with it left NULL
+	Bytes        int64
+
+	IndexesDeferred int    // Secondary indexes a bulk load dropped, to be rebuilt after the commit
+	StagingTable    string // Committed staging table of a parallel copy, to drop once the outer transaction ends
+}
+
+// StartImportRun records the start of an import attempt and returns its run ID
+func (c *DB) StartImportRun(ctx context.Context, db Querier, resource ckan.Resource, attempt int) (int64, error) {
+	q := `
+	INSERT INTO import_runs (resource_id, package_id, resource_name, attempt)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id
+	`
+	var id int64
+	if err := db.QueryRow(ctx, q, resource.Id, resource.PackageId, resource.Name, attempt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to start import run: %w", err)
+	}
+	return id, nil
+}
+
+// FinishImportRun records the outcome of an import attempt; importErr is nil on success
+func (c *DB) FinishImportRun(ctx context.Context, db Querier, id int64, stats ImportStats, importErr error) error {
+	q := `
+	UPDATE import_runs SET
+		finished_at = now(),
+		rows_read = $2,
+		rows_inserted = $3,
+		rows_rejected = $4,
+		bytes = $5,
+		error = $6
+	WHERE id = $1
+	`
+	var errMsg *string
+	if importErr != nil {
+		msg := importErr.Error()
+		errMsg = &msg
+	}
+	_, err := db.Exec(ctx, q, id,
+		stats.RowsRead, stats.RowsInserted, stats.RowsRejected, stats.Bytes,
+		errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to finish import run: %w", err)
+	}
+	return nil
+}
+
+// ImportRuns returns the most recent import runs, newest first, optionally for one resource name.
+// The name matches regardless of case and with or without its .csv extension, so od_firme
+// finds the runs of OD_FIRME.CSV.
+func (c *DB) ImportRuns(ctx context.Context, db Querier, resourceName string, limit int) ([]goovern.ImportRun, error) {
+	q := `
+	SELECT
+		id,
+		resource_id,
+		package_id,
+		resource_name,
+		attempt,
+		CASE
+			WHEN finished_at IS NULL THEN 'running'
+			WHEN error IS NULL THEN 'succeeded'
+			ELSE 'failed'
+		END AS status,
+		started_at,
+		finished_at,
+		COALESCE(extract(epoch FROM duration), 0)::float8 AS duration_seconds,
+		rows_read,
+		rows_inserted,
+		rows_rejected,
+		bytes,
+		COALESCE(error, '') AS error
+	FROM import_runs
+	WHERE $1 = '' OR lower(resource_name) IN (lower($1), lower($1) || '.csv')
+	ORDER BY started_at DESC, id DESC
+	LIMIT $2
+	`
+	return collect[goovern.ImportRun](ctx, db, q, resourceName, limit)
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SetBulkLoad enables bulk loading of empty tables: the rows are staged in an unlogged table and
+// the secondary indexes of the target are dropped until the import committed. It speeds up the
+// initial load of a table and full reloads; other imports into a populated one are unaffected.
+func (c *DB) SetBulkLoad(enabled bool) {
+	c.bulkLoad = enabled
+}
+
+// bulkLoadable reports whether an import into table is the initial load of an empty table, which bulk loading applies to
+func (c *DB) bulkLoadable(ctx context.Context, db Querier, table string) (bool, error) {
+	if !c.bulkLoad {
+		return false, nil
+	}
+	var empty bool
+	q := fmt.Sprintf(`SELECT NOT EXISTS (SELECT 1 FROM %s)`, pgx.Identifier{table}.Sanitize())
+	if err := db.QueryRow(ctx, q).Scan(&empty); err != nil {
+		return false, fmt.Errorf("failed to check if %s is empty: %w", table, err)
+	}
+	return empty, nil
+}
+
+// deferIndexes drops the secondary indexes of table, recording their definitions so that
+// RebuildDeferredIndexes recreates them. Unique indexes stay, they back the conflict keys.
+func deferIndexes(ctx context.Context, tx Tx, table string, logger *slog.Logger) (int, error) {
+	q := `
+	SELECT i.relname, pg_get_indexdef(ix.indexrelid)
+	FROM pg_index ix
+	JOIN pg_class i ON i.oid = ix.indexrelid
+	WHERE ix.indrelid = $1::regclass
+		AND NOT ix.indisunique
+		AND NOT ix.indisprimary
+		AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid)
+	ORDER BY i.relname
+	`
+	rows, err := tx.Query(ctx, q, table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query indexes of %s: %w", table, err)
+	}
+	indexes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([2]string, error) {
+		var index [2]string
+		err := row.Scan(&index[0], &index[1])
+		return index, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect indexes of %s: %w", table, err)
+	}
+
+	for _, index := range indexes {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO import_deferred_indexes (index_name, table_name, definition)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (index_name) DO UPDATE SET definition = EXCLUDED.definition, dropped_at = now()
+		`, index[0], table, index[1])
+		if err != nil {
+			return 0, fmt.Errorf("failed to record index %s: %w", index[0], err)
+		}
+		if _, err = tx.Exec(ctx, `DROP INDEX `+pgx.Identifier{index[0]}.Sanitize()); err != nil {
+			return 0, fmt.Errorf("failed to drop index %s: %w", index[0], err)
+		}
+	}
+	if len(indexes) > 0 {
+		logger.Info("Dropped secondary indexes for bulk load", "table", table, "indexes", len(indexes))
+	}
+	return len(indexes), nil
+}
+
+// RebuildDeferredIndexes concurrently recreates the indexes dropped by bulk loads and returns how
+// many were rebuilt. It runs outside a transaction, since concurrent index builds cannot run in
+// one, on a connection of pool holding a session lock so that rebuilds run one at a time.
+func (c *DB) RebuildDeferredIndexes(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	db, err := pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer db.Release()
+
+	// A concurrent rebuild would see the indexes this one builds as invalid and drop them
+	if _, err = db.Exec(ctx, `SELECT pg_advisory_lock(hashtext('goovern_rebuild_indexes'))`); err != nil {
+		return 0, fmt.Errorf("failed to lock index rebuild: %w", err)
+	}
+	defer func() {
+		if _, err := db.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock(hashtext('goovern_rebuild_indexes'))`); err != nil {
+			// Closing the connection releases the lock with its session
+			c.logger.Warn("Failed to unlock index rebuild", "error", err)
+			db.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	type deferredIndex struct {
+		IndexName  string `db:"index_name"`
+		TableName  string `db:"table_name"`
+		Definition string `db:"definition"`
+	}
+	indexes, err := collect[deferredIndex](ctx, db,
+		`SELECT index_name, table_name, definition FROM import_deferred_indexes ORDER BY dropped_at, index_name`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deferred indexes: %w", err)
+	}
+
+	rebuilt := 0
+	for _, index := range indexes {
+		logger := c.logger.With("index", index.IndexName, "table", index.TableName)
+
+		create, ok := strings.CutPrefix(index.Definition, "CREATE INDEX ")
+		if !ok {
+			return rebuilt, fmt.Errorf("unexpected definition of index %s: %s", index.IndexName, index.Definition)
+		}
+
+		// An interrupted concurrent build leaves an invalid index behind. One still being built by
+		// another backend is invalid too until it finishes, so it is left to that build.
+		var invalid, building bool
+		err = db.QueryRow(ctx, `
+			SELECT
+				EXISTS (
+					SELECT 1 FROM pg_index ix JOIN pg_class i ON i.oid = ix.indexrelid
+					WHERE i.relname = $1 AND NOT ix.indisvalid
+				),
+				EXISTS (
+					SELECT 1 FROM pg_stat_progress_create_index p JOIN pg_class i ON i.oid = p.index_relid
+					WHERE i.relname = $1
+				)
+		`, index.IndexName).Scan(&invalid, &building)
+		if err != nil {
+			return rebuilt, fmt.Errorf("failed to check index %s: %w", index.IndexName, err)
+		}
+		if building {
+			logger.Warn("Skipping index another backend is building")
+			continue
+		}
+		if invalid {
+			logger.Warn("Dropping invalid index left by an interrupted rebuild")
+			if _, err = db.Exec(ctx, `DROP INDEX CONCURRENTLY IF EXISTS `+pgx.Identifier{index.IndexName}.Sanitize()); err != nil {
+				return rebuilt, fmt.Errorf("failed to drop invalid index %s: %w", index.IndexName, err)
+			}
+		}
+
+		logger.Info("Rebuilding index")
+		if _, err = db.Exec(ctx, "CREATE INDEX CONCURRENTLY IF NOT EXISTS "+create); err != nil {
+			return rebuilt, fmt.Errorf("failed to rebuild index %s: %w", index.IndexName, err)
+		}
+		if _, err = db.Exec(ctx, `DELETE FROM import_deferred_indexes WHERE index_name = $1`, index.IndexName); err != nil {
+			return rebuilt, fmt.Errorf("failed to clear deferred index %s: %w", index.IndexName, err)
+		}
+		rebuilt++
+	}
+	return rebuilt, nil
+}
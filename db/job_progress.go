@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// ImportProgress is called periodically during an import with the rows and bytes read so far
+type ImportProgress func(rows, bytes int64)
+
+// SaveJobProgress stores the latest progress of a running job
+func (c *DB) SaveJobProgress(ctx context.Context, db Querier, progress goovern.JobProgress) error {
+	q := `
+	INSERT INTO job_progress (job_id, kind, resource_name, rows, bytes, total_bytes)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (job_id) DO UPDATE SET
+		rows = EXCLUDED.rows,
+		bytes = EXCLUDED.bytes,
+		total_bytes = EXCLUDED.total_bytes,
+		updated_at = now()
+	`
+	_, err := db.Exec(ctx, q, progress.JobID, progress.Kind, progress.ResourceName,
+		progress.Rows, progress.Bytes, progress.TotalBytes)
+	if err != nil {
+		return fmt.Errorf("failed to save job progress: %w", err)
+	}
+	return nil
+}
+
+// DeleteJobProgress removes the progress of a job that returned
+func (c *DB) DeleteJobProgress(ctx context.Context, db Querier, jobID int64) error {
+	if _, err := db.Exec(ctx, `DELETE FROM job_progress WHERE job_id = $1`, jobID); err != nil {
+		return fmt.Errorf("failed to delete job progress: %w", err)
+	}
+	return nil
+}
+
+// RunningJobs returns the running River jobs with their latest progress, oldest first. The ETA
+// extrapolates the byte rate since the first report and is NULL while the size is unknown.
+func (c *DB) RunningJobs(ctx context.Context, db Querier) ([]goovern.JobProgress, error) {
+	q := `
+	SELECT
+		j.id AS job_id,
+		j.kind,
+		COALESCE(p.resource_name, j.args->'resourceGetter'->>'name', '') AS resource_name,
+		j.attempt,
+		COALESCE(j.attempted_at, j.created_at) AS started_at,
+		p.updated_at,
+		COALESCE(p.rows, 0) AS rows,
+		COALESCE(p.bytes, 0) AS bytes,
+		COALESCE(p.total_bytes, 0) AS total_bytes,
+		CASE WHEN p.bytes > 0 AND p.total_bytes > p.bytes THEN
+			extract(epoch FROM p.updated_at - p.started_at) * (p.total_bytes - p.bytes) / p.bytes
+		END::float8 AS eta_seconds
+	FROM river_job j
+	LEFT JOIN job_progress p ON p.job_id = j.id
+	WHERE j.state = 'running'
+	ORDER BY started_at, j.id
+	`
+	return collect[goovern.JobProgress](ctx, db, q)
+}
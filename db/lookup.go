@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// LookupTaxIDs enriches many tax IDs at once, returning one row per input in the same order.
+// IDs may carry the RO prefix; unmatched IDs come back with Found set to false.
+func (c *DB) LookupTaxIDs(ctx context.Context, db Querier, taxIDs []string) ([]goovern.Enrichment, error) {
+	if len(taxIDs) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, len(taxIDs))
+	for i, id := range taxIDs {
+		normalized[i] = goovern.NormalizeCUI(id)
+	}
+
+	// ONRC has no "main activity" flag, so only the lowest authorized code of the newest CAEN version is reported
+	q := `
+	SELECT
+		q.query,
+		c.registration_code IS NOT NULL AS found,
+		COALESCE(c.tax_id, '') AS tax_id,
+		COALESCE(c.name, '') AS name,
+		COALESCE(c.registration_code, '') AS registration_code,
+		COALESCE(st.status, '') AS status,
+		COALESCE(c.county, '') AS county,
+		COALESCE(c.legal_form, '') AS legal_form,
+		COALESCE(aa.authorized_caen_code, '') AS first_authorized_caen
+	FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS q(query, tax_id, ord)
+	LEFT JOIN LATERAL (
+		SELECT *
+		FROM companies
+		WHERE tax_id = q.tax_id
+		ORDER BY registration_code
+		LIMIT 1
+	) c ON true
+	LEFT JOIN LATERAL (
+		SELECT string_agg(cs.name, ', ' ORDER BY sh.status_code) AS status
+		FROM company_status_history sh
+		JOIN company_statuses cs ON cs.code = sh.status_code
+		WHERE sh.registration_code = c.registration_code
+	) st ON true
+	LEFT JOIN LATERAL (
+		SELECT authorized_caen_code
+		FROM authorized_activities
+		WHERE registration_code = c.registration_code
+		ORDER BY caen_version DESC, authorized_caen_code
+		LIMIT 1
+	) aa ON true
+	ORDER BY q.ord
+	`
+
+	return collect[goovern.Enrichment](ctx, db, q, taxIDs, normalized)
+}
+
+// CompaniesByTaxIDs looks up many tax IDs at once, like CompanyByTaxID does for one. The
+// companies are keyed by the normalized tax ID; unmatched IDs are missing from the map.
+func (c *DB) CompaniesByTaxIDs(ctx context.Context, db Querier, taxIDs []string) (map[string]goovern.Company, error) {
+	normalized := make([]string, len(taxIDs))
+	for i, id := range taxIDs {
+		normalized[i] = goovern.NormalizeCUI(id)
+	}
+
+	q := `SELECT DISTINCT ON (tax_id) ` + companyColumns + ` FROM companies WHERE tax_id = ANY($1) ORDER BY tax_id, registration_code`
+	rows, err := db.Query(ctx, q, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	companies := make(map[string]goovern.Company)
+	for rows.Next() {
+		var comp goovern.Company
+		if err = scanCompany(rows, &comp); err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies[comp.TaxID] = comp
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	return companies, nil
+}
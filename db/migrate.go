@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"io/fs"
 	"log/slog"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -47,3 +48,21 @@ func Migrate(ctx context.Context, pool *pgxpool.Pool, logger *slog.Logger) error
 	logger.Info("migrations completed successfully")
 	return nil
 }
+
+// PendingMigrations reports whether any embedded goose migration has not been applied yet
+func PendingMigrations(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return false, err
+	}
+
+	sqlDB := stdlib.OpenDBFromPool(pool)
+	defer sqlDB.Close()
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, sqlDB, fsys)
+	if err != nil {
+		return false, fmt.Errorf("failed to create goose provider: %w", err)
+	}
+
+	return provider.HasPending(ctx)
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Enable trigram matching for typo-tolerant search on company names
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram index on the unaccented, lowercased name; queries must use the same expression
+CREATE INDEX IF NOT EXISTS idx_companies_name_trgm
+    ON companies USING GIN (lower(immutable_unaccent(name)) gin_trgm_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_companies_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
+
+-- +goose StatementEnd
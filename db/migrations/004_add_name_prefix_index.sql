@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- B-tree index for anchored "name starts with" lookups used by search-as-you-type.
+-- text_pattern_ops makes LIKE 'prefix%' indexable regardless of the database collation.
+CREATE INDEX IF NOT EXISTS idx_companies_name_prefix
+    ON companies (lower(immutable_unaccent(name)) text_pattern_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_companies_name_prefix;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Parse the date formats found in ONRC exports (YYYY-MM-DD, DD.MM.YYYY, DD/MM/YYYY),
+-- returning NULL for anything else so filters never fail on a malformed row
+CREATE OR REPLACE FUNCTION parse_onrc_date(value text)
+RETURNS date
+LANGUAGE plpgsql
+IMMUTABLE PARALLEL SAFE STRICT
+AS $$
+BEGIN
+    value := btrim(value);
+    IF value ~ '^\d{4}-\d{2}-\d{2}' THEN
+        RETURN to_date(substr(value, 1, 10), 'YYYY-MM-DD');
+    ELSIF value ~ '^\d{2}[./-]\d{2}[./-]\d{4}' THEN
+        RETURN to_date(substr(value, 1, 10), 'DD.MM.YYYY');
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP FUNCTION IF EXISTS parse_onrc_date(text);
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Unaccented full-text search on representative names. The 'simple' configuration
+-- skips stemming and stop words, which only get in the way for personal names.
+ALTER TABLE legal_representatives
+ADD COLUMN authorized_person_tsvector tsvector
+GENERATED ALWAYS AS (to_tsvector('simple', immutable_unaccent(authorized_person))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_legal_representatives_authorized_person_tsvector
+    ON legal_representatives USING GIN (authorized_person_tsvector);
+
+ALTER TABLE family_business_representatives
+ADD COLUMN name_tsvector tsvector
+GENERATED ALWAYS AS (to_tsvector('simple', immutable_unaccent(name))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_family_business_representatives_name_tsvector
+    ON family_business_representatives USING GIN (name_tsvector);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_family_business_representatives_name_tsvector;
+ALTER TABLE family_business_representatives DROP COLUMN IF EXISTS name_tsvector;
+
+DROP INDEX IF EXISTS idx_legal_representatives_authorized_person_tsvector;
+ALTER TABLE legal_representatives DROP COLUMN IF EXISTS authorized_person_tsvector;
+
+-- +goose StatementEnd
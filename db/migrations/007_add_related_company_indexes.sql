@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Normalize a registered address into a comparison key: unaccented, lowercase and
+-- stripped of punctuation and spacing, so "Str. Mihai Eminescu, Nr. 5" and
+-- "STR MIHAI EMINESCU NR 5" match. Fields stay positional to avoid false matches.
+CREATE OR REPLACE FUNCTION normalize_address(
+    county text, locality text, street_name text, street_number text,
+    building text, staircase text, floor text, apartment text
+)
+RETURNS text
+LANGUAGE sql
+IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT regexp_replace(
+        lower(immutable_unaccent(concat_ws('|',
+            COALESCE(county, ''), COALESCE(locality, ''), COALESCE(street_name, ''), COALESCE(street_number, ''),
+            COALESCE(building, ''), COALESCE(staircase, ''), COALESCE(floor, ''), COALESCE(apartment, '')
+        ))),
+        '[^a-z0-9|]+', '', 'g'
+    );
+$$;
+
+-- Companies without a street name only carry a locality, which would link whole towns
+CREATE INDEX IF NOT EXISTS idx_companies_normalized_address
+    ON companies (normalize_address(county, locality, street_name, street_number, building, staircase, floor, apartment))
+    WHERE street_name IS NOT NULL AND street_name <> '';
+
+-- A representative is identified across companies by name and birth date
+CREATE INDEX IF NOT EXISTS idx_legal_representatives_person
+    ON legal_representatives (lower(immutable_unaccent(authorized_person)), birth_date)
+    WHERE birth_date IS NOT NULL AND birth_date <> '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_legal_representatives_person;
+DROP INDEX IF EXISTS idx_companies_normalized_address;
+DROP FUNCTION IF EXISTS normalize_address(text, text, text, text, text, text, text, text);
+
+-- +goose StatementEnd
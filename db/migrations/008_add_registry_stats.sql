@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Aggregates for the statistics dashboard, refreshed after every import. Each view
+-- has a unique index so it can be refreshed CONCURRENTLY without blocking readers.
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_county AS
+SELECT COALESCE(NULLIF(btrim(county), ''), '(unknown)') AS label, count(*) AS companies
+FROM companies
+GROUP BY 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_county_label ON stats_by_county (label);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_legal_form AS
+SELECT COALESCE(NULLIF(btrim(legal_form), ''), '(unknown)') AS label, count(*) AS companies
+FROM companies
+GROUP BY 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_legal_form_label ON stats_by_legal_form (label);
+
+-- Companies authorized for at least one activity in each CAEN section, per CAEN version
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_caen_section AS
+SELECT cc.caen_version, cc.section, count(DISTINCT aa.registration_code) AS companies
+FROM authorized_activities aa
+JOIN caen_codes cc ON cc.class = aa.authorized_caen_code AND cc.caen_version = aa.caen_version
+GROUP BY cc.caen_version, cc.section;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_section_key ON stats_by_caen_section (caen_version, section);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_registration_year AS
+SELECT extract(year FROM parse_onrc_date(registration_date))::int AS year, count(*) AS companies
+FROM companies
+WHERE parse_onrc_date(registration_date) IS NOT NULL
+GROUP BY 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_registration_year_year ON stats_by_registration_year (year);
+
+-- One row of totals. A radiated status wins over any other; companies that are
+-- neither radiated nor in operation (insolvent, suspended, ...) count as other.
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_summary AS
+WITH flags AS (
+    SELECT
+        c.registration_code,
+        COALESCE(bool_or(immutable_unaccent(cs.name) ILIKE '%radiat%'), false) AS radiated,
+        COALESCE(bool_or(immutable_unaccent(cs.name) ILIKE '%functiune%'), false) AS active
+    FROM companies c
+    LEFT JOIN company_status_history sh ON sh.registration_code = c.registration_code
+    LEFT JOIN company_statuses cs ON cs.code = sh.status_code
+    GROUP BY c.registration_code
+)
+SELECT
+    1 AS id,
+    count(*) AS companies,
+    count(*) FILTER (WHERE active AND NOT radiated) AS active,
+    count(*) FILTER (WHERE radiated) AS radiated,
+    count(*) FILTER (WHERE NOT active AND NOT radiated) AS other,
+    now() AS refreshed_at
+FROM flags;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_summary_id ON stats_summary (id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP MATERIALIZED VIEW IF EXISTS stats_summary;
+DROP MATERIALIZED VIEW IF EXISTS stats_by_registration_year;
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_section;
+DROP MATERIALIZED VIEW IF EXISTS stats_by_legal_form;
+DROP MATERIALIZED VIEW IF EXISTS stats_by_county;
+
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Companies authorized per CAEN code at every level of the classification, for the
+-- CAEN browser. Divisions and groups are the 2 and 3 digit prefixes of the class,
+-- and a company is counted once per node however many of its classes fall under it.
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_caen_code AS
+WITH activities AS (
+    SELECT
+        aa.registration_code,
+        aa.caen_version,
+        COALESCE(cc.section, '') AS section,
+        left(aa.authorized_caen_code, 2) AS division,
+        left(aa.authorized_caen_code, 3) AS "group",
+        aa.authorized_caen_code AS class
+    FROM authorized_activities aa
+    LEFT JOIN LATERAL (
+        SELECT section
+        FROM caen_codes
+        WHERE class = aa.authorized_caen_code AND caen_version = aa.caen_version
+        LIMIT 1
+    ) cc ON true
+)
+SELECT
+    caen_version,
+    CASE
+        WHEN GROUPING(section) = 0 THEN 'section'
+        WHEN GROUPING(division) = 0 THEN 'division'
+        WHEN GROUPING("group") = 0 THEN 'group'
+        ELSE 'class'
+    END AS level,
+    COALESCE(section, division, "group", class) AS code,
+    count(DISTINCT registration_code) AS companies
+FROM activities
+GROUP BY GROUPING SETS (
+    (caen_version, section),
+    (caen_version, division),
+    (caen_version, "group"),
+    (caen_version, class)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_code_key ON stats_by_caen_code (caen_version, level, code);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_code;
+
+-- +goose StatementEnd
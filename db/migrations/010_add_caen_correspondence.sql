@@ -0,0 +1,138 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Correspondence between CAEN Rev.2 and Rev.3 classes, many-to-many. Loaded at startup
+-- from the mapping bundled with the binary, not from ONRC.
+CREATE TABLE IF NOT EXISTS caen_correspondence (
+    rev2_code TEXT NOT NULL,
+    rev3_code TEXT NOT NULL,
+    PRIMARY KEY (rev2_code, rev3_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_caen_correspondence_rev3 ON caen_correspondence (rev3_code);
+
+-- The correspondence in both directions, by caen_versions code
+CREATE OR REPLACE VIEW caen_translations AS
+SELECT 2 AS from_version, rev2_code AS from_code, 3 AS to_version, rev3_code AS to_code FROM caen_correspondence
+UNION ALL
+SELECT 3, rev3_code, 2, rev2_code FROM caen_correspondence;
+
+-- Every activity of a company in its own CAEN version and translated into the other one,
+-- so a company authorized only under Rev.2 still counts under the matching Rev.3 classes
+CREATE OR REPLACE VIEW authorized_activities_all_versions AS
+SELECT registration_code, caen_version, authorized_caen_code
+FROM authorized_activities
+UNION
+SELECT aa.registration_code, t.to_version, t.to_code
+FROM authorized_activities aa
+JOIN caen_translations t ON t.from_version = aa.caen_version AND t.from_code = aa.authorized_caen_code;
+
+-- Rebuild the CAEN statistics over both versions
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_section;
+
+CREATE MATERIALIZED VIEW stats_by_caen_section AS
+SELECT cc.caen_version, cc.section, count(DISTINCT aa.registration_code) AS companies
+FROM authorized_activities_all_versions aa
+JOIN caen_codes cc ON cc.class = aa.authorized_caen_code AND cc.caen_version = aa.caen_version
+GROUP BY cc.caen_version, cc.section;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_section_key ON stats_by_caen_section (caen_version, section);
+
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_code;
+
+CREATE MATERIALIZED VIEW stats_by_caen_code AS
+WITH activities AS (
+    SELECT
+        aa.registration_code,
+        aa.caen_version,
+        COALESCE(cc.section, '') AS section,
+        left(aa.authorized_caen_code, 2) AS division,
+        left(aa.authorized_caen_code, 3) AS "group",
+        aa.authorized_caen_code AS class
+    FROM authorized_activities_all_versions aa
+    LEFT JOIN LATERAL (
+        SELECT section
+        FROM caen_codes
+        WHERE class = aa.authorized_caen_code AND caen_version = aa.caen_version
+        LIMIT 1
+    ) cc ON true
+)
+SELECT
+    caen_version,
+    CASE
+        WHEN GROUPING(section) = 0 THEN 'section'
+        WHEN GROUPING(division) = 0 THEN 'division'
+        WHEN GROUPING("group") = 0 THEN 'group'
+        ELSE 'class'
+    END AS level,
+    COALESCE(section, division, "group", class) AS code,
+    count(DISTINCT registration_code) AS companies
+FROM activities
+GROUP BY GROUPING SETS (
+    (caen_version, section),
+    (caen_version, division),
+    (caen_version, "group"),
+    (caen_version, class)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_code_key ON stats_by_caen_code (caen_version, level, code);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_code;
+
+CREATE MATERIALIZED VIEW stats_by_caen_code AS
+WITH activities AS (
+    SELECT
+        aa.registration_code,
+        aa.caen_version,
+        COALESCE(cc.section, '') AS section,
+        left(aa.authorized_caen_code, 2) AS division,
+        left(aa.authorized_caen_code, 3) AS "group",
+        aa.authorized_caen_code AS class
+    FROM authorized_activities aa
+    LEFT JOIN LATERAL (
+        SELECT section
+        FROM caen_codes
+        WHERE class = aa.authorized_caen_code AND caen_version = aa.caen_version
+        LIMIT 1
+    ) cc ON true
+)
+SELECT
+    caen_version,
+    CASE
+        WHEN GROUPING(section) = 0 THEN 'section'
+        WHEN GROUPING(division) = 0 THEN 'division'
+        WHEN GROUPING("group") = 0 THEN 'group'
+        ELSE 'class'
+    END AS level,
+    COALESCE(section, division, "group", class) AS code,
+    count(DISTINCT registration_code) AS companies
+FROM activities
+GROUP BY GROUPING SETS (
+    (caen_version, section),
+    (caen_version, division),
+    (caen_version, "group"),
+    (caen_version, class)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_code_key ON stats_by_caen_code (caen_version, level, code);
+
+DROP MATERIALIZED VIEW IF EXISTS stats_by_caen_section;
+
+CREATE MATERIALIZED VIEW stats_by_caen_section AS
+SELECT cc.caen_version, cc.section, count(DISTINCT aa.registration_code) AS companies
+FROM authorized_activities aa
+JOIN caen_codes cc ON cc.class = aa.authorized_caen_code AND cc.caen_version = aa.caen_version
+GROUP BY cc.caen_version, cc.section;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_caen_section_key ON stats_by_caen_section (caen_version, section);
+
+DROP VIEW IF EXISTS authorized_activities_all_versions;
+DROP VIEW IF EXISTS caen_translations;
+DROP TABLE IF EXISTS caen_correspondence;
+
+-- +goose StatementEnd
@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Store registration and birth dates as DATE so they can be range-filtered and sorted.
+-- Existing values are parsed with parse_onrc_date; anything unparseable becomes NULL.
+-- New imports parse dates before loading them (see ImportConfig.Transforms).
+
+-- Objects depending on the converted columns are rebuilt around the change
+DROP MATERIALIZED VIEW IF EXISTS stats_by_registration_year;
+DROP INDEX IF EXISTS idx_legal_representatives_person;
+
+ALTER TABLE companies
+    ALTER COLUMN registration_date TYPE DATE USING parse_onrc_date(registration_date);
+ALTER TABLE legal_representatives
+    ALTER COLUMN birth_date TYPE DATE USING parse_onrc_date(birth_date);
+ALTER TABLE family_business_representatives
+    ALTER COLUMN birth_date TYPE DATE USING parse_onrc_date(birth_date);
+
+CREATE INDEX IF NOT EXISTS idx_companies_registration_date
+    ON companies (registration_date);
+
+CREATE INDEX IF NOT EXISTS idx_legal_representatives_person
+    ON legal_representatives (lower(immutable_unaccent(authorized_person)), birth_date)
+    WHERE birth_date IS NOT NULL;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_registration_year AS
+SELECT extract(year FROM registration_date)::int AS year, count(*) AS companies
+FROM companies
+WHERE registration_date IS NOT NULL
+GROUP BY 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_registration_year_year ON stats_by_registration_year (year);
+
+-- Values the import could not convert, kept for inspection. The row itself is
+-- imported with the column left NULL.
+CREATE TABLE IF NOT EXISTS import_rejects (
+    id          BIGSERIAL   PRIMARY KEY,
+    resource    TEXT        NOT NULL,
+    line        BIGINT      NOT NULL,
+    column_name TEXT        NOT NULL,
+    value       TEXT        NOT NULL,
+    error       TEXT        NOT NULL,
+    rejected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_rejects_resource
+    ON import_rejects (resource, rejected_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS import_rejects;
+
+DROP MATERIALIZED VIEW IF EXISTS stats_by_registration_year;
+DROP INDEX IF EXISTS idx_legal_representatives_person;
+DROP INDEX IF EXISTS idx_companies_registration_date;
+
+ALTER TABLE family_business_representatives
+    ALTER COLUMN birth_date TYPE TEXT USING to_char(birth_date, 'YYYY-MM-DD');
+ALTER TABLE legal_representatives
+    ALTER COLUMN birth_date TYPE TEXT USING to_char(birth_date, 'YYYY-MM-DD');
+ALTER TABLE companies
+    ALTER COLUMN registration_date TYPE TEXT USING to_char(registration_date, 'YYYY-MM-DD');
+
+CREATE INDEX IF NOT EXISTS idx_legal_representatives_person
+    ON legal_representatives (lower(immutable_unaccent(authorized_person)), birth_date)
+    WHERE birth_date IS NOT NULL AND birth_date <> '';
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_by_registration_year AS
+SELECT extract(year FROM parse_onrc_date(registration_date))::int AS year, count(*) AS companies
+FROM companies
+WHERE parse_onrc_date(registration_date) IS NOT NULL
+GROUP BY 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_by_registration_year_year ON stats_by_registration_year (year);
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- CSV headers that did not match the target table at import time, so format changes
+-- in the ONRC exports get noticed. imported is false when the drift failed the import.
+CREATE TABLE IF NOT EXISTS import_schema_drift (
+    id              BIGSERIAL   PRIMARY KEY,
+    resource        TEXT        NOT NULL,
+    table_name      TEXT        NOT NULL,
+    unknown_columns TEXT[]      NOT NULL,
+    missing_columns TEXT[]      NOT NULL,
+    imported        BOOLEAN     NOT NULL,
+    detected_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_schema_drift_resource
+    ON import_schema_drift (resource, detected_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS import_schema_drift;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per import attempt, written by the import worker. A run without finished_at
+-- is still in progress, or its worker died before recording the outcome.
+CREATE TABLE IF NOT EXISTS import_runs (
+    id            BIGSERIAL   PRIMARY KEY,
+    resource_id   UUID        NOT NULL,
+    package_id    UUID,
+    resource_name TEXT        NOT NULL,
+    attempt       INT         NOT NULL DEFAULT 1,
+    started_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at   TIMESTAMPTZ,
+    duration      INTERVAL GENERATED ALWAYS AS (finished_at - started_at) STORED,
+    rows_read     BIGINT      NOT NULL DEFAULT 0,
+    rows_inserted BIGINT      NOT NULL DEFAULT 0,
+    rows_rejected BIGINT      NOT NULL DEFAULT 0,
+    bytes         BIGINT      NOT NULL DEFAULT 0,
+    error         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_runs_started_at ON import_runs (started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_import_runs_resource_name ON import_runs (resource_name, started_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS import_runs;
+
+-- +goose StatementEnd
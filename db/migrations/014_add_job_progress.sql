@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Latest progress of running download and import jobs, written periodically by the workers
+-- outside their transactions and removed when the job returns
+CREATE TABLE IF NOT EXISTS job_progress (
+    job_id        BIGINT      PRIMARY KEY,
+    kind          TEXT        NOT NULL,
+    resource_name TEXT        NOT NULL,
+    rows          BIGINT      NOT NULL DEFAULT 0,
+    bytes         BIGINT      NOT NULL DEFAULT 0,
+    total_bytes   BIGINT      NOT NULL DEFAULT 0,
+    started_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS job_progress;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Secondary indexes dropped by a bulk load, with their definitions, until they are rebuilt
+-- concurrently after the import committed
+CREATE TABLE IF NOT EXISTS import_deferred_indexes (
+    index_name TEXT        PRIMARY KEY,
+    table_name TEXT        NOT NULL,
+    definition TEXT        NOT NULL,
+    dropped_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS import_deferred_indexes;
+
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Checksum of the correspondence last loaded, so startup skips reloading an unchanged one
+CREATE TABLE IF NOT EXISTS caen_correspondence_source (
+    checksum  TEXT        PRIMARY KEY,
+    loaded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Classes with the same code and name in both versions correspond too. They back the
+-- correspondence table for the Rev.2 classes it does not list.
+CREATE OR REPLACE VIEW caen_translations AS
+WITH pairs AS (
+    SELECT rev2_code, rev3_code FROM caen_correspondence
+    UNION
+    SELECT r2.class, r3.class
+    FROM caen_codes r2
+    JOIN caen_codes r3 ON r3.caen_version = 3
+        AND r3.class = r2.class
+        AND lower(immutable_unaccent(trim(r3.name))) = lower(immutable_unaccent(trim(r2.name)))
+    WHERE r2.caen_version = 2
+        AND r2.class <> ''
+        AND NOT EXISTS (SELECT 1 FROM caen_correspondence c WHERE c.rev2_code = r2.class)
+)
+SELECT 2 AS from_version, rev2_code AS from_code, 3 AS to_version, rev3_code AS to_code FROM pairs
+UNION ALL
+SELECT 3, rev3_code, 2, rev2_code FROM pairs;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+CREATE OR REPLACE VIEW caen_translations AS
+SELECT 2 AS from_version, rev2_code AS from_code, 3 AS to_version, rev3_code AS to_code FROM caen_correspondence
+UNION ALL
+SELECT 3, rev3_code, 2, rev2_code FROM caen_correspondence;
+
+DROP TABLE IF EXISTS caen_correspondence_source;
+
+-- +goose StatementEnd
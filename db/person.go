@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// SearchPeople finds legal and family business representatives by name, one row per company they represent
+func (c *DB) SearchPeople(ctx context.Context, db Querier, searchTerm string, limit int) (_ []goovern.Representation, err error) {
+	defer func() { observeSearch("person", err) }()
+
+	searchTerm = strings.TrimSpace(searchTerm)
+	if searchTerm == "" {
+		return nil, errors.New("search term cannot be empty")
+	}
+
+	q := `
+	WITH query AS (
+		SELECT websearch_to_tsquery('simple', immutable_unaccent($1)) AS query
+	), people AS (
+		SELECT
+			lr.authorized_person AS person,
+			COALESCE(to_char(lr.birth_date, 'YYYY-MM-DD'), '') AS birth_date,
+			COALESCE(lr.role, '') AS role,
+			'legal' AS kind,
+			lr.registration_code,
+			ts_rank(lr.authorized_person_tsvector, query.query) AS rank
+		FROM legal_representatives lr, query
+		WHERE lr.authorized_person_tsvector @@ query.query
+		UNION ALL
+		SELECT
+			fb.name AS person,
+			COALESCE(to_char(fb.birth_date, 'YYYY-MM-DD'), '') AS birth_date,
+			COALESCE(fb.role, '') AS role,
+			'family_business' AS kind,
+			fb.registration_code,
+			ts_rank(fb.name_tsvector, query.query) AS rank
+		FROM family_business_representatives fb, query
+		WHERE fb.name_tsvector @@ query.query
+	)
+	SELECT
+		p.person,
+		p.birth_date,
+		p.role,
+		p.kind,
+		p.registration_code,
+		c.name AS company_name,
+		COALESCE(c.tax_id, '') AS tax_id,
+		p.rank
+	FROM people p
+	JOIN companies c ON c.registration_code = p.registration_code
+	ORDER BY p.rank DESC, p.person, p.birth_date, c.name
+	LIMIT $2
+	`
+
+	return collect[goovern.Representation](ctx, db, q, searchTerm, limit)
+}
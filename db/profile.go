@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// CompanyProfile returns a company with its representatives, activities, status history and branches
+func (c *DB) CompanyProfile(ctx context.Context, db Querier, registrationCode string) (goovern.CompanyProfile, bool, error) {
+	var profile goovern.CompanyProfile
+
+	comp, found, err := c.Company(ctx, db, registrationCode)
+	if err != nil || !found {
+		return profile, found, err
+	}
+	profile.Company = comp
+
+	if profile.LegalRepresentatives, err = c.LegalRepresentatives(ctx, db, registrationCode); err != nil {
+		return profile, false, fmt.Errorf("failed to load legal representatives: %w", err)
+	}
+	if profile.FamilyBusinessRepresentatives, err = c.FamilyBusinessRepresentatives(ctx, db, registrationCode); err != nil {
+		return profile, false, fmt.Errorf("failed to load family business representatives: %w", err)
+	}
+	if profile.Activities, err = c.AuthorizedActivities(ctx, db, registrationCode); err != nil {
+		return profile, false, fmt.Errorf("failed to load activities: %w", err)
+	}
+	if profile.StatusHistory, err = c.StatusHistory(ctx, db, registrationCode); err != nil {
+		return profile, false, fmt.Errorf("failed to load status history: %w", err)
+	}
+	if profile.ForeignBranches, err = c.ForeignBranches(ctx, db, registrationCode); err != nil {
+		return profile, false, fmt.Errorf("failed to load foreign branches: %w", err)
+	}
+
+	return profile, true, nil
+}
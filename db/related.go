@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// RelatedCompanies returns the companies sharing a legal representative (same name and birth date)
+// or the exact registered address with the given company, up to limit rows
+func (c *DB) RelatedCompanies(ctx context.Context, db Querier, registrationCode string, limit int) ([]goovern.RelatedCompany, error) {
+	q := `
+	WITH by_representative AS (
+		SELECT DISTINCT ON (other.registration_code, other.authorized_person)
+			other.registration_code,
+			'representative' AS reason,
+			other.authorized_person AS link
+		FROM legal_representatives lr
+		JOIN legal_representatives other
+			ON lower(immutable_unaccent(other.authorized_person)) = lower(immutable_unaccent(lr.authorized_person))
+			AND other.birth_date = lr.birth_date
+			AND other.birth_date IS NOT NULL
+		WHERE lr.registration_code = $1
+			AND lr.birth_date IS NOT NULL
+			AND other.registration_code <> $1
+	),
+	by_address AS (
+		SELECT
+			other.registration_code,
+			'address' AS reason,
+			concat_ws(', ',
+				NULLIF(concat_ws(' ', other.street_name, other.street_number), ''),
+				NULLIF(other.locality, ''),
+				NULLIF(other.county, '')
+			) AS link
+		FROM companies target
+		JOIN companies other
+			ON normalize_address(other.county, other.locality, other.street_name, other.street_number,
+				other.building, other.staircase, other.floor, other.apartment)
+			= normalize_address(target.county, target.locality, target.street_name, target.street_number,
+				target.building, target.staircase, target.floor, target.apartment)
+			AND other.street_name IS NOT NULL AND other.street_name <> ''
+		WHERE target.registration_code = $1
+			AND target.street_name IS NOT NULL AND target.street_name <> ''
+			AND other.registration_code <> $1
+	)
+	SELECT ` + companyColumns + `, related.reason, related.link
+	FROM (
+		SELECT * FROM by_representative
+		UNION ALL
+		SELECT * FROM by_address
+	) related
+	JOIN companies USING (registration_code)
+	ORDER BY related.reason DESC, related.link, name, registration_code
+	LIMIT $2
+	`
+
+	rows, err := db.Query(ctx, q, registrationCode, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query related companies: %w", err)
+	}
+	defer rows.Close()
+
+	var related []goovern.RelatedCompany
+	for rows.Next() {
+		var rc goovern.RelatedCompany
+		if err = scanCompany(rows, &rc.Company, &rc.Reason, &rc.Link); err != nil {
+			return nil, fmt.Errorf("failed to scan related company: %w", err)
+		}
+		related = append(related, rc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate related companies: %w", err)
+	}
+	return related, nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetFullReload enables full reloads: when an update brings a new snapshot of every configured
+// resource, each table is truncated and bulk loaded from it instead of only gaining new rows.
+func (c *DB) SetFullReload(enabled bool) {
+	c.fullReload = enabled
+}
+
+// FullReload reports whether importing the given resources reloads the tables from scratch,
+// which needs full reloads enabled and a new snapshot of every configured resource. Reloading
+// only some of them would leave the tables referencing them emptied by the truncate.
+func (c *DB) FullReload(names []string) bool {
+	if !c.fullReload {
+		return false
+	}
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+	for name := range c.imports {
+		if !present[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateForReload empties table inside the import transaction, together with the tables
+// referencing it, which the same reload imports again afterwards. Reloads of one tier run at
+// once and their cascades overlap, so a lock held until the commit takes them one at a time.
+func truncateForReload(ctx context.Context, tx Tx, table string, logger *slog.Logger) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('goovern_reload'))`); err != nil {
+		return fmt.Errorf("failed to acquire reload lock: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `TRUNCATE `+pgx.Identifier{table}.Sanitize()+` CASCADE`); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", table, err)
+	}
+	logger.Info("Truncated table for full reload", "table", table)
+	return nil
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestFullReload(t *testing.T) {
+	c := New(slog.New(slog.DiscardHandler))
+	all := slices.Collect(maps.Keys(c.imports))
+
+	if c.FullReload(all) {
+		t.Error("FullReload reloads with full reloads disabled")
+	}
+
+	c.SetFullReload(true)
+	if !c.FullReload(all) {
+		t.Error("FullReload does not reload a snapshot of every resource")
+	}
+	if !c.FullReload(append(all, all[0])) {
+		t.Error("FullReload does not reload a snapshot with a resource in several packages")
+	}
+	if c.FullReload(all[1:]) {
+		t.Errorf("FullReload reloads a snapshot without %s", all[0])
+	}
+	if c.FullReload(nil) {
+		t.Error("FullReload reloads without resources")
+	}
+}
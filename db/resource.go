@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -39,7 +40,8 @@ func (c *DB) SaveResource(ctx context.Context, tx Tx, resource ckan.Resource) er
 		cache_url = EXCLUDED.cache_url,
 		datagovro_download_url = EXCLUDED.datagovro_download_url,
 		datastore_active = EXCLUDED.datastore_active,
-		revision_id = EXCLUDED.revision_id
+		revision_id = EXCLUDED.revision_id,
+		updated_at = NOW()
 	`
 
 	_, err := tx.Exec(ctx, q,
@@ -96,3 +98,30 @@ func (c *DB) Resource(ctx context.Context, tx Tx, id uuid.UUID) (ckan.Resource,
 
 	return res, true, nil
 }
+
+// LastImports returns the time each resource name was last successfully imported
+func (c *DB) LastImports(ctx context.Context, db Querier) (map[string]time.Time, error) {
+	q := `
+	SELECT name, MAX(updated_at)
+	FROM resources
+	GROUP BY name
+	`
+
+	rows, err := db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imports := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		var updatedAt time.Time
+		if err = rows.Scan(&name, &updatedAt); err != nil {
+			return nil, err
+		}
+		imports[name] = updatedAt
+	}
+
+	return imports, rows.Err()
+}
@@ -2,108 +2,401 @@ package db
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/metrics"
+)
+
+type SearchMode int
+
+const (
+	// SearchFuzzy blends trigram word similarity with full-text rank, tolerating typos and partial words
+	SearchFuzzy SearchMode = iota
+	// SearchFullText only matches whole (stemmed) words through the full-text index
+	SearchFullText
+	// SearchPrefix treats every word as a prefix, for search-as-you-type
+	SearchPrefix
 )
 
-//SELECT name, registration_code, ts_rank(name_tsvector, query) AS rank
-//FROM companies,
-//     to_tsquery('romanian', immutable_unaccent('maxim & ionut & persoana & fizica & autorizata')) query
-//WHERE name_tsvector @@ query
-//ORDER BY rank DESC
-//LIMIT 20;
+// SearchQuery describes a company search. Term may be empty when at least one filter is set.
+type SearchQuery struct {
+	Term  string
+	Mode  SearchMode
+	Limit int
+	After *SearchCursor // Continue after this cursor, nil for the first page
+
+	SearchFilters
+}
+
+// SearchCursor marks the last row of a page in search order
+type SearchCursor struct {
+	Rank             float32 `json:"r"`
+	Name             string  `json:"n"`
+	RegistrationCode string  `json:"c"`
+}
+
+// Encode returns an opaque, URL-safe form of the cursor
+func (c SearchCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
 
-func (c *DB) Search(ctx context.Context, db Querier, searchTerm string, limit int) ([]goovern.Company, error) {
-	// Split search term into words and join with & for AND search
-	words := strings.Fields(searchTerm)
-	if len(words) == 0 {
-		return nil, fmt.Errorf("search term cannot be empty")
+// DecodeSearchCursor parses a cursor produced by SearchCursor.Encode
+func DecodeSearchCursor(s string) (SearchCursor, error) {
+	var c SearchCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("malformed cursor")
 	}
-	tsQuery := strings.Join(words, " | ")
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, errors.New("malformed cursor")
+	}
+	return c, nil
+}
 
-	// Check if search term looks like a CUI (tax ID) - typically numeric with optional RO prefix
-	// If it's purely numeric or starts with RO, prioritize exact CUI match
-	// Otherwise, use full-text search on name and also check if CUI contains the search term
+// SearchFilters narrows a search down; zero values leave a filter unset
+type SearchFilters struct {
+	County         string    // Case and diacritic insensitive
+	Locality       string    // Case and diacritic insensitive
+	LegalForm      string    // Case and diacritic insensitive, e.g. SRL
+	CAEN           string    // Authorized CAEN class, e.g. 6201, or a division or group prefix such as 62, in either CAEN version
+	CAENVersion    int       // Version CAEN is a code of, matching activities of the other version through the correspondence; 0 matches codes of both
+	Status         string    // Status name fragment, code, or alias such as "active"
+	RegisteredFrom time.Time // Inclusive
+	RegisteredTo   time.Time // Inclusive
+	HasWebsite     *bool
+}
+
+// IsZero reports whether no filter is set
+func (f SearchFilters) IsZero() bool {
+	return f == SearchFilters{}
+}
+
+// statusAliases maps English shorthands to the ONRC status names they stand for
+var statusAliases = map[string]string{
+	"active":     "functiune",
+	"radiated":   "radiata",
+	"insolvent":  "insolventa",
+	"dissolved":  "dizolvare",
+	"suspended":  "suspendare",
+	"liquidated": "lichidare",
+}
+
+// Search returns one page of matches, along with the cursor of the next page when there is one.
+// A term that is a valid CUI is looked up exactly first, and searched as a name if no company has it.
+func (c *DB) Search(ctx context.Context, db Querier, sq SearchQuery) (_ []goovern.Company, next *SearchCursor, err error) {
+	defer func() { observeSearch("company", err) }()
+
+	_, isCUI := exactCUI(sq.Term)
+	results, next, err := search(ctx, db, sq, isCUI)
+	if err == nil && isCUI && len(results) == 0 {
+		return search(ctx, db, sq, false)
+	}
+	return results, next, err
+}
+
+func search(ctx context.Context, db Querier, sq SearchQuery, cuiLookup bool) (_ []goovern.Company, next *SearchCursor, err error) {
+	b, err := newSearchBuilder(sq, cuiLookup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keyset pagination over (rank DESC, name, registration_code); the subquery lets the
+	// cursor compare against the computed rank
+	keyset := "true"
+	if sq.After != nil {
+		r, n, rc := b.arg(sq.After.Rank), b.arg(sq.After.Name), b.arg(sq.After.RegistrationCode)
+		keyset = fmt.Sprintf("rank < %s OR (rank = %s AND (name, registration_code) > (%s, %s))", r, r, n, rc)
+	}
+
+	// One extra row tells whether another page follows
 	query := `
-		SELECT
-			registration_code,
-			name,
-			COALESCE(tax_id, '') as tax_id,
-			COALESCE(registration_date, '') as registration_date,
-			COALESCE(euid, '') as euid,
-			COALESCE(legal_form, '') as legal_form,
-			COALESCE(country, '') as country,
-			COALESCE(county, '') as county,
-			COALESCE(locality, '') as locality,
-			COALESCE(street_name, '') as street_name,
-			COALESCE(street_number, '') as street_number,
-			COALESCE(building, '') as building,
-			COALESCE(staircase, '') as staircase,
-			COALESCE(floor, '') as floor,
-			COALESCE(apartment, '') as apartment,
-			COALESCE(postal_code, '') as postal_code,
-			COALESCE(sector, '') as sector,
-			COALESCE(address_details, '') as address_details,
-			COALESCE(website, '') as website,
-			COALESCE(parent_company_country, '') as parent_company_country,
-			CASE
-				WHEN tax_id ILIKE $1 || '%' THEN 1.0
-				ELSE ts_rank(name_tsvector, query)
-			END AS rank
-		FROM companies
-		LEFT JOIN LATERAL (
-			SELECT to_tsquery('romanian', immutable_unaccent($2)) AS query
-		) q ON true
-		WHERE
-			tax_id ILIKE '%' || $1 || '%'
-			OR name_tsvector @@ query
-		ORDER BY rank DESC, name
-		LIMIT $3
-	`
-
-	rows, err := db.Query(ctx, query, searchTerm, tsQuery, limit)
+		SELECT * FROM (
+			SELECT ` + companyColumns + `, (` + b.rank + `)::real AS rank
+			FROM companies` + b.from + `
+			WHERE ` + b.condition() + `
+		) results
+		WHERE ` + keyset + `
+		ORDER BY rank DESC, name, registration_code
+		LIMIT ` + b.arg(sq.Limit+1)
+
+	rows, err := db.Query(ctx, query, b.args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute search query: %w", err)
+	}
+
+	results, err := scanCompanies(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(results) > sq.Limit {
+		results = results[:sq.Limit]
+		last := results[len(results)-1]
+		next = &SearchCursor{Rank: last.Rank, Name: last.Name, RegistrationCode: last.RegistrationCode}
+	}
+
+	return results, next, nil
+}
+
+// SearchCount returns the total number of companies matching a search, ignoring pagination
+func (c *DB) SearchCount(ctx context.Context, db Querier, sq SearchQuery) (int, error) {
+	_, isCUI := exactCUI(sq.Term)
+	count, err := searchCount(ctx, db, sq, isCUI)
+	if err == nil && isCUI && count == 0 {
+		return searchCount(ctx, db, sq, false)
+	}
+	return count, err
+}
+
+func searchCount(ctx context.Context, db Querier, sq SearchQuery, cuiLookup bool) (int, error) {
+	b, err := newSearchBuilder(sq, cuiLookup)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute search query: %w", err)
+		return 0, err
+	}
+
+	query := `SELECT count(*) FROM companies` + b.from + ` WHERE ` + b.condition()
+
+	var count int
+	if err = db.QueryRow(ctx, query, b.args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+	return count, nil
+}
+
+// searchBuilder assembles the search query; every user value goes through arg as a bind parameter
+type searchBuilder struct {
+	args  []any
+	from  string
+	rank  string
+	where []string
+}
+
+// newSearchBuilder builds the query of a search; cuiLookup matches a CUI term exactly instead of
+// searching names
+func newSearchBuilder(sq SearchQuery, cuiLookup bool) (*searchBuilder, error) {
+	term := strings.TrimSpace(sq.Term)
+	if term == "" && sq.SearchFilters.IsZero() {
+		return nil, errors.New("search term cannot be empty")
+	}
+
+	b := &searchBuilder{}
+	if cui, ok := exactCUI(term); ok && cuiLookup {
+		b.rank = "1.0"
+		b.where = append(b.where, "tax_id = "+b.arg(cui))
+	} else {
+		b.term(term, sq.Mode)
+	}
+	b.filters(sq.SearchFilters)
+	return b, nil
+}
+
+func (b *searchBuilder) condition() string {
+	return strings.Join(b.where, "\n\t\t\t\tAND ")
+}
+
+func (b *searchBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// term adds the text match and rank expression for the given mode
+func (b *searchBuilder) term(term string, mode SearchMode) {
+	if term == "" {
+		b.rank = "0.0"
+		b.where = append(b.where, "true")
+		return
+	}
+
+	// Only numeric terms can match a CUI; an empty parameter lets the planner drop the unindexable LIKE
+	cui := goovern.NormalizeCUI(term)
+	if !isDigits(cui) {
+		cui = ""
+	}
+	p := b.arg(cui)
+	taxIDMatch := fmt.Sprintf("(%s <> '' AND tax_id LIKE '%%' || %s || '%%')", p, p)
+	taxIDRank := fmt.Sprintf("WHEN %s <> '' AND tax_id LIKE %s || '%%' THEN 1.0", p, p)
+
+	switch mode {
+	case SearchPrefix:
+		// Names starting with the term come from idx_companies_name_prefix and rank first;
+		// word prefixes anywhere in the name come from the GIN index on name_tsvector
+		tsq, like := b.arg(prefixTSQuery(term)), b.arg(escapeLike(term))
+		startsWith := fmt.Sprintf("lower(immutable_unaccent(name)) LIKE lower(immutable_unaccent(%s)) || '%%'", like)
+		b.from = fmt.Sprintf(",\n\t\t\tto_tsquery('romanian', immutable_unaccent(%s)) query", tsq)
+		b.rank = fmt.Sprintf("CASE %s ELSE (%s)::int + ts_rank(name_tsvector, query) END", taxIDRank, startsWith)
+		b.where = append(b.where, fmt.Sprintf("(%s OR %s OR name_tsvector @@ query)", taxIDMatch, startsWith))
+
+	case SearchFullText:
+		// websearch_to_tsquery accepts any input, so user text can never break the query
+		b.from = fmt.Sprintf(",\n\t\t\twebsearch_to_tsquery('romanian', immutable_unaccent(%s)) query", b.arg(term))
+		b.rank = fmt.Sprintf("CASE %s ELSE ts_rank(name_tsvector, query) END", taxIDRank)
+		b.where = append(b.where, fmt.Sprintf("(%s OR name_tsvector @@ query)", taxIDMatch))
+
+	default:
+		// The trigram operator works on lower(immutable_unaccent(name)) to hit idx_companies_name_trgm
+		t := b.arg(term)
+		similar := fmt.Sprintf("lower(immutable_unaccent(%s)) <%% lower(immutable_unaccent(name))", t)
+		b.from = fmt.Sprintf(",\n\t\t\twebsearch_to_tsquery('romanian', immutable_unaccent(%s)) query", t)
+		b.rank = fmt.Sprintf(
+			"CASE %s ELSE ts_rank(name_tsvector, query) + word_similarity(lower(immutable_unaccent(%s)), lower(immutable_unaccent(name))) END",
+			taxIDRank, t,
+		)
+		b.where = append(b.where, fmt.Sprintf("(%s OR name_tsvector @@ query OR %s)", taxIDMatch, similar))
+	}
+}
+
+// exactCUI reports whether a term is a CUI with a valid check digit, which the indexed exact
+// lookup finds. Short numbers can pass the check by chance, so a miss falls back to a name search.
+func exactCUI(term string) (string, bool) {
+	cui := goovern.NormalizeCUI(strings.TrimSpace(term))
+	return cui, goovern.ValidateCUI(cui) == nil
+}
+
+// filters adds one condition per set filter
+func (b *searchBuilder) filters(f SearchFilters) {
+	equalFold := func(column, value string) string {
+		return fmt.Sprintf("lower(immutable_unaccent(%s)) = lower(immutable_unaccent(%s))", column, b.arg(value))
+	}
+
+	if f.County != "" {
+		b.where = append(b.where, equalFold("county", f.County))
+	}
+	if f.Locality != "" {
+		b.where = append(b.where, equalFold("locality", f.Locality))
+	}
+	if f.LegalForm != "" {
+		b.where = append(b.where, equalFold("legal_form", f.LegalForm))
+	}
+	if f.CAEN != "" {
+		// Classes have four digits; shorter codes select a whole division or group.
+		// Activities of the other CAEN version match through the correspondence table.
+		op, pattern := "=", f.CAEN
+		if len(f.CAEN) < 4 && isDigits(f.CAEN) {
+			op, pattern = "LIKE", f.CAEN+"%"
+		}
+		code := b.arg(pattern)
+		own, translated := "true", "true"
+		if f.CAENVersion != 0 {
+			version := b.arg(f.CAENVersion)
+			own, translated = "aa.caen_version = "+version, "to_version = "+version
+		}
+		b.where = append(b.where, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM authorized_activities aa
+				WHERE aa.registration_code = companies.registration_code
+					AND ((%[3]s AND aa.authorized_caen_code %[1]s %[2]s) OR (aa.caen_version, aa.authorized_caen_code) IN (
+						SELECT from_version, from_code FROM caen_translations WHERE %[4]s AND to_code %[1]s %[2]s
+					))
+			)`, op, code, own, translated))
+	}
+	if f.Status != "" {
+		b.where = append(b.where, b.statusCondition(f.Status))
+	}
+	if !f.RegisteredFrom.IsZero() {
+		b.where = append(b.where, "registration_date >= "+b.arg(f.RegisteredFrom)+"::date")
 	}
+	if !f.RegisteredTo.IsZero() {
+		b.where = append(b.where, "registration_date <= "+b.arg(f.RegisteredTo)+"::date")
+	}
+	if f.HasWebsite != nil {
+		op := "="
+		if *f.HasWebsite {
+			op = "<>"
+		}
+		b.where = append(b.where, fmt.Sprintf("COALESCE(website, '') %s ''", op))
+	}
+}
+
+// statusCondition matches companies with a recorded status by code, alias or name fragment
+func (b *searchBuilder) statusCondition(status string) string {
+	if code, err := strconv.Atoi(status); err == nil {
+		return fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM company_status_history sh
+				WHERE sh.registration_code = companies.registration_code
+					AND sh.status_code = %s
+			)`, b.arg(code))
+	}
+
+	if alias, ok := statusAliases[strings.ToLower(status)]; ok {
+		status = alias
+	}
+	return fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM company_status_history sh
+				JOIN company_statuses cs ON cs.code = sh.status_code
+				WHERE sh.registration_code = companies.registration_code
+					AND immutable_unaccent(cs.name) ILIKE '%%' || immutable_unaccent(%s) || '%%'
+			)`, b.arg(escapeLike(status)))
+}
+
+// scanCompanies collects rows selected with companyColumns followed by a rank column
+func scanCompanies(rows pgx.Rows) ([]goovern.Company, error) {
 	defer rows.Close()
 
 	var results []goovern.Company
 	for rows.Next() {
 		var comp goovern.Company
-		err = rows.Scan(
-			&comp.RegistrationCode,
-			&comp.Name,
-			&comp.TaxID,
-			&comp.RegistrationDate,
-			&comp.EUID,
-			&comp.LegalForm,
-			&comp.Country,
-			&comp.County,
-			&comp.Locality,
-			&comp.StreetName,
-			&comp.StreetNumber,
-			&comp.Building,
-			&comp.Staircase,
-			&comp.Floor,
-			&comp.Apartment,
-			&comp.PostalCode,
-			&comp.Sector,
-			&comp.AddressDetails,
-			&comp.Website,
-			&comp.ParentCompanyCountry,
-			&comp.Rank,
-		)
-		if err != nil {
+		if err := scanCompany(rows, &comp, &comp.Rank); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		results = append(results, comp)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	return results, nil
 }
+
+// prefixTSQuery turns every word into a quoted prefix lexeme ('electr':*) joined with AND.
+// Only letters and digits survive, so user input can never produce tsquery syntax.
+func prefixTSQuery(term string) string {
+	var lexemes []string
+	for _, word := range strings.Fields(term) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, word)
+		if word != "" {
+			lexemes = append(lexemes, "'"+word+"':*")
+		}
+	}
+	return strings.Join(lexemes, " & ")
+}
+
+// escapeLike escapes the LIKE wildcards in s so it only matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func observeSearch(target string, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.SearchQueries.WithLabelValues(target, outcome).Inc()
+}
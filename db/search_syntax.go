@@ -0,0 +1,124 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ParseSearchQuery splits user input into a free-text term and key:value filters, e.g.
+//
+//	dedeman county:BACAU caen:4752 status:active from:2010 website:yes
+//
+// Values containing spaces can be quoted (county:"Satu Mare"). Tokens with an unknown key
+// are kept as part of the search term.
+func ParseSearchQuery(input string) (SearchQuery, error) {
+	var sq SearchQuery
+	var words []string
+
+	for _, token := range tokenize(input) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			words = append(words, token)
+			continue
+		}
+		value = strings.Trim(value, `"`)
+
+		var err error
+		switch strings.ToLower(key) {
+		case "county", "judet":
+			sq.County = value
+		case "locality", "city", "localitate":
+			sq.Locality = value
+		case "form", "legal_form":
+			sq.LegalForm = value
+		case "caen":
+			sq.CAEN = value
+		case "status":
+			sq.Status = value
+		case "from":
+			sq.RegisteredFrom, err = parseFilterDate(value, false)
+		case "to":
+			sq.RegisteredTo, err = parseFilterDate(value, true)
+		case "website", "web":
+			sq.HasWebsite, err = parseFilterBool(value)
+		default:
+			words = append(words, token)
+		}
+		if err != nil {
+			return sq, fmt.Errorf("invalid %s filter: %w", key, err)
+		}
+	}
+
+	sq.Term = strings.Join(words, " ")
+	return sq, nil
+}
+
+// tokenize splits on whitespace, keeping double-quoted sections together
+func tokenize(input string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// parseFilterDate accepts a full date, a month or a year. Upper bounds of partial
+// dates extend to the end of the period so "to:2020" includes all of 2020.
+func parseFilterDate(value string, end bool) (time.Time, error) {
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+
+	for _, l := range layouts {
+		t, err := time.Parse(l.layout, value)
+		if err != nil {
+			continue
+		}
+		if end {
+			t = t.AddDate(l.years, l.months, l.days-1)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("expected YYYY, YYYY-MM or YYYY-MM-DD, got %q", value)
+}
+
+func parseFilterBool(value string) (*bool, error) {
+	var b bool
+	switch strings.ToLower(value) {
+	case "yes", "true", "1", "da":
+		b = true
+	case "no", "false", "0", "nu":
+		b = false
+	default:
+		return nil, fmt.Errorf("expected yes or no, got %q", value)
+	}
+	return &b, nil
+}
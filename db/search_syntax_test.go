@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSearchQueryDateBounds(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		input    string
+		from, to time.Time
+	}{
+		{input: "from:2020", from: date("2020-01-01")},
+		{input: "to:2020", to: date("2020-12-31")},
+		{input: "to:2020-02", to: date("2020-02-29")},
+		{input: "to:2021-02", to: date("2021-02-28")},
+		{input: "to:2020-12", to: date("2020-12-31")},
+		{input: "to:2020-06-15", to: date("2020-06-15")},
+		{input: "from:2019-03 to:2019-03", from: date("2019-03-01"), to: date("2019-03-31")},
+		{input: "from:2018-05-01 to:2018", from: date("2018-05-01"), to: date("2018-12-31")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sq, err := ParseSearchQuery(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q): %v", tt.input, err)
+			}
+			if !sq.RegisteredFrom.Equal(tt.from) {
+				t.Errorf("RegisteredFrom = %s, want %s", sq.RegisteredFrom, tt.from)
+			}
+			if !sq.RegisteredTo.Equal(tt.to) {
+				t.Errorf("RegisteredTo = %s, want %s", sq.RegisteredTo, tt.to)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryInvalidDate(t *testing.T) {
+	for _, input := range []string{"to:2020-13", "from:20", "to:yesterday"} {
+		if _, err := ParseSearchQuery(input); err == nil {
+			t.Errorf("ParseSearchQuery(%q) succeeded, want an error", input)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCAENFilterVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  SearchFilters
+		args     []any
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "any version",
+			filters:  SearchFilters{CAEN: "6201"},
+			args:     []any{"6201"},
+			excludes: []string{"aa.caen_version =", "to_version ="},
+		},
+		{
+			name:     "one version",
+			filters:  SearchFilters{CAEN: "6201", CAENVersion: 3},
+			args:     []any{"6201", 3},
+			contains: []string{"aa.caen_version = $2 AND aa.authorized_caen_code = $1", "to_version = $2 AND to_code = $1"},
+		},
+		{
+			name:     "division of one version",
+			filters:  SearchFilters{CAEN: "62", CAENVersion: 2},
+			args:     []any{"62%", 2},
+			contains: []string{"aa.caen_version = $2 AND aa.authorized_caen_code LIKE $1", "to_version = $2 AND to_code LIKE $1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newSearchBuilder(SearchQuery{SearchFilters: tt.filters}, false)
+			if err != nil {
+				t.Fatalf("newSearchBuilder: %v", err)
+			}
+			if !slices.Equal(b.args, tt.args) {
+				t.Errorf("args = %v, want %v", b.args, tt.args)
+			}
+			condition := b.condition()
+			for _, s := range tt.contains {
+				if !strings.Contains(condition, s) {
+					t.Errorf("condition does not contain %q:\n%s", s, condition)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(condition, s) {
+					t.Errorf("condition contains %q:\n%s", s, condition)
+				}
+			}
+		})
+	}
+}
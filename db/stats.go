@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+// statsViews are the materialized views behind RegistryStats, refreshed together
+var statsViews = []string{
+	"stats_by_county",
+	"stats_by_legal_form",
+	"stats_by_caen_section",
+	"stats_by_caen_code",
+	"stats_by_registration_year",
+	"stats_summary",
+}
+
+// RegistryStats reads the registry aggregates from their materialized views. CAEN sections
+// are reported for the newest CAEN version, named from their section row when there is one.
+func (c *DB) RegistryStats(ctx context.Context, db Querier) (goovern.RegistryStats, error) {
+	var stats goovern.RegistryStats
+
+	q := `SELECT companies, active, radiated, other, refreshed_at FROM stats_summary`
+	err := db.QueryRow(ctx, q).Scan(&stats.Companies, &stats.Active, &stats.Radiated, &stats.Other, &stats.RefreshedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return stats, fmt.Errorf("failed to query stats summary: %w", err)
+	}
+
+	if stats.ByCounty, err = collect[goovern.StatCount](ctx, db,
+		`SELECT label, companies FROM stats_by_county ORDER BY companies DESC, label`); err != nil {
+		return stats, fmt.Errorf("failed to query stats by county: %w", err)
+	}
+
+	if stats.ByLegalForm, err = collect[goovern.StatCount](ctx, db,
+		`SELECT label, companies FROM stats_by_legal_form ORDER BY companies DESC, label`); err != nil {
+		return stats, fmt.Errorf("failed to query stats by legal form: %w", err)
+	}
+
+	if stats.ByRegistrationYear, err = collect[goovern.StatCount](ctx, db,
+		`SELECT year::text AS label, companies FROM stats_by_registration_year ORDER BY year`); err != nil {
+		return stats, fmt.Errorf("failed to query stats by registration year: %w", err)
+	}
+
+	q = `SELECT COALESCE(max(caen_version), 0) FROM stats_by_caen_section`
+	if err = db.QueryRow(ctx, q).Scan(&stats.CAENVersion); err != nil {
+		return stats, fmt.Errorf("failed to query CAEN version: %w", err)
+	}
+
+	q = `
+	SELECT
+		s.section || COALESCE(' ' || (
+			SELECT name FROM caen_codes cc
+			WHERE cc.section = s.section AND cc.caen_version = s.caen_version AND COALESCE(cc.division, '') = ''
+			LIMIT 1
+		), '') AS label,
+		s.companies
+	FROM stats_by_caen_section s
+	WHERE s.caen_version = $1
+	ORDER BY s.section
+	`
+	if stats.ByCAENSection, err = collect[goovern.StatCount](ctx, db, q, stats.CAENVersion); err != nil {
+		return stats, fmt.Errorf("failed to query stats by CAEN section: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RefreshStats recomputes the statistics views unless a refresh started after since has already
+// run, which lets a burst of imports share one refresh. It reports whether it refreshed.
+func (c *DB) RefreshStats(ctx context.Context, db Tx, since time.Time) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent refreshes would only repeat each other's work
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('goovern_refresh_stats'))`); err != nil {
+		return false, fmt.Errorf("failed to lock stats refresh: %w", err)
+	}
+
+	var refreshedAt time.Time
+	err = tx.QueryRow(ctx, `SELECT refreshed_at FROM stats_summary`).Scan(&refreshedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("failed to query last stats refresh: %w", err)
+	}
+	if refreshedAt.After(since) {
+		return false, nil
+	}
+
+	for _, view := range statsViews {
+		c.logger.Debug("Refreshing materialized view", "view", view)
+		if _, err = tx.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY `+pgx.Identifier{view}.Sanitize()); err != nil {
+			return false, fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit stats refresh: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/csv"
+)
+
+// Transform converts a CSV value into the value copied into its column. A nil result is
+// copied as NULL; an error rejects the value, which is recorded and copied as NULL.
+type Transform = csv.Transform
+
+// Trim removes leading and trailing whitespace
+func Trim(value string) (any, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// NullIfEmpty turns empty values into NULL
+func NullIfEmpty(value string) (any, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return value, nil
+}
+
+// Upper converts the value to upper case
+func Upper(value string) (any, error) {
+	return strings.ToUpper(value), nil
+}
+
+// onrcDateLayouts are the date formats found in ONRC exports, matched on the first 10 characters
+var onrcDateLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006", "02-01-2006"}
+
+// ParseDate parses an ONRC date, ignoring any time of day after it
+func ParseDate(value string) (any, error) {
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	for _, layout := range onrcDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized date: %q", value)
+}
+
+// ParseInt parses a base 10 integer
+func ParseInt(value string) (any, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer: %q", value)
+	}
+	return n, nil
+}
+
+// NormalizeCUI strips whitespace and the RO VAT prefix from a tax ID
+func NormalizeCUI(value string) (any, error) {
+	return goovern.NormalizeCUI(value), nil
+}
+
+// Chain applies transforms in order, each to the string the previous one returned.
+// A NULL ends the chain, so transforms returning anything other than a string go last.
+func Chain(transforms ...Transform) Transform {
+	return func(value string) (any, error) {
+		var out any = value
+		for _, t := range transforms {
+			s, ok := out.(string)
+			if !ok {
+				break
+			}
+			var err error
+			if out, err = t(s); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+}
+
+var (
+	cleanText    = Chain(Trim, NullIfEmpty)
+	requiredText = Transform(Trim)
+)
+
+// columnTransforms picks the transform of every column: whitespace is trimmed and empty values
+// become NULL, except in NOT NULL columns, before the transform configured for the column
+func columnTransforms(columns []string, configured map[string]Transform, table []tableColumn) []Transform {
+	transforms := make([]Transform, len(columns))
+	for i, column := range columns {
+		clean := cleanText
+		if slices.ContainsFunc(table, func(c tableColumn) bool { return c.Name == column && c.NotNull }) {
+			clean = requiredText
+		}
+		transforms[i] = clean
+		if t := configured[column]; t != nil {
+			transforms[i] = Chain(clean, t)
+		}
+	}
+	return transforms
+}
+
+// reject is a value a Transform could not convert
+type reject struct {
+	line   int64
+	column string
+	value  string
+	err    error
+}
+
+// maxRejects caps how many rejects of one import are stored
+const maxRejects = 10000
+
+// rejectCollector gathers the rejects of an import, keeping the first maxRejects
+type rejectCollector struct {
+	columns  []string
+	rejects  []reject
+	count    int64
+	rows     int64
+	lastLine int64
+}
+
+func (r *rejectCollector) add(line int64, field int, value string, err error) {
+	r.count++
+	if line != r.lastLine {
+		r.rows++
+		r.lastLine = line
+	}
+	if len(r.rejects) < maxRejects {
+		r.rejects = append(r.rejects, reject{line: line, column: r.columns[field], value: value, err: err})
+	}
+}
+
+// saveRejects records the values an import rejected
+func saveRejects(ctx context.Context, db Querier, resource string, rejects []reject) error {
+	if len(rejects) == 0 {
+		return nil
+	}
+	_, err := db.CopyFrom(ctx,
+		pgx.Identifier{"import_rejects"},
+		[]string{"resource", "line", "column_name", "value", "error"},
+		pgx.CopyFromSlice(len(rejects), func(i int) ([]any, error) {
+			r := rejects[i]
+			return []any{resource, r.line, r.column, r.value, r.err.Error()}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save import rejects: %w", err)
+	}
+	return nil
+}
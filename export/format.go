@@ -0,0 +1,137 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ionut-maxim/goovern"
+)
+
+var CompanyHeader = []string{
+	"tax_id",
+	"name",
+	"registration_code",
+	"registration_date",
+	"euid",
+	"legal_form",
+	"country",
+	"county",
+	"locality",
+	"street_name",
+	"street_number",
+	"building",
+	"staircase",
+	"floor",
+	"apartment",
+	"postal_code",
+	"sector",
+	"address_details",
+	"website",
+	"parent_company_country",
+}
+
+func CompanyRecord(c goovern.Company) []string {
+	return []string{
+		c.TaxID,
+		c.Name,
+		c.RegistrationCode,
+		c.RegistrationDate,
+		c.EUID,
+		c.LegalForm,
+		c.Country,
+		c.County,
+		c.Locality,
+		c.StreetName,
+		c.StreetNumber,
+		c.Building,
+		c.Staircase,
+		c.Floor,
+		c.Apartment,
+		c.PostalCode,
+		c.Sector,
+		c.AddressDetails,
+		c.Website,
+		c.ParentCompanyCountry,
+	}
+}
+
+var RepresentationHeader = []string{
+	"person",
+	"birth_date",
+	"role",
+	"kind",
+	"registration_code",
+	"company_name",
+	"tax_id",
+}
+
+func RepresentationRecord(r goovern.Representation) []string {
+	return []string{
+		r.Person,
+		r.BirthDate,
+		r.Role,
+		r.Kind,
+		r.RegistrationCode,
+		r.CompanyName,
+		r.TaxID,
+	}
+}
+
+var ImportRunHeader = []string{
+	"id",
+	"resource_name",
+	"status",
+	"started_at",
+	"finished_at",
+	"duration_seconds",
+	"rows_read",
+	"rows_inserted",
+	"rows_rejected",
+	"bytes",
+	"error",
+}
+
+func ImportRunRecord(r goovern.ImportRun) []string {
+	var finished string
+	if r.FinishedAt != nil {
+		finished = r.FinishedAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.FormatInt(r.ID, 10),
+		r.ResourceName,
+		r.Status,
+		r.StartedAt.Format(time.RFC3339),
+		finished,
+		strconv.FormatFloat(r.DurationSeconds, 'f', 1, 64),
+		strconv.FormatInt(r.RowsRead, 10),
+		strconv.FormatInt(r.RowsInserted, 10),
+		strconv.FormatInt(r.RowsRejected, 10),
+		strconv.FormatInt(r.Bytes, 10),
+		r.Error,
+	}
+}
+
+// WriteCSV writes a header and one record per item
+func WriteCSV[T any](w io.Writer, header []string, items []T, record func(T) []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write(record(item)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes v as indented JSON
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
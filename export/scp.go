@@ -0,0 +1,62 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish/scp"
+)
+
+type scpHandler struct {
+	store *Store
+}
+
+// SCPHandler serves a user's exports to `scp host:<name> .`, read-only and without directories
+func (s *Store) SCPHandler() scp.CopyToClientHandler {
+	return &scpHandler{store: s}
+}
+
+func (h *scpHandler) Glob(s ssh.Session, pattern string) ([]string, error) {
+	pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "./"), "/")
+
+	var matches []string
+	for _, f := range h.store.Files(s.User()) {
+		ok, err := path.Match(pattern, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, f.Name)
+		}
+	}
+	return matches, nil
+}
+
+func (h *scpHandler) WalkDir(ssh.Session, string, fs.WalkDirFunc) error {
+	return errors.New("recursive copy is not supported")
+}
+
+func (h *scpHandler) NewDirEntry(ssh.Session, string) (*scp.DirEntry, error) {
+	return nil, errors.New("directories are not supported")
+}
+
+func (h *scpHandler) NewFileEntry(s ssh.Session, name string) (*scp.FileEntry, func() error, error) {
+	f, ok := h.store.File(s.User(), name)
+	if !ok {
+		return nil, nil, fmt.Errorf("no such export: %s", name)
+	}
+	return &scp.FileEntry{
+		Name:     f.Name,
+		Filepath: f.Name,
+		Mode:     0o644,
+		Size:     int64(len(f.Data)),
+		Reader:   bytes.NewReader(f.Data),
+		Mtime:    f.ModTime.Unix(),
+		Atime:    f.ModTime.Unix(),
+	}, nil, nil
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/pkg/sftp"
+)
+
+// SFTPHandler serves a user's exports as a flat, read-only SFTP directory
+func (s *Store) SFTPHandler(logger *slog.Logger) ssh.SubsystemHandler {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	return func(sess ssh.Session) {
+		h := &sftpHandler{store: s, user: sess.User()}
+		server := sftp.NewRequestServer(sess, sftp.Handlers{
+			FileGet:  h,
+			FilePut:  h,
+			FileCmd:  h,
+			FileList: h,
+		})
+		if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
+			logger.Error("SFTP session failed", "user", sess.User(), "error", err)
+		}
+		_ = server.Close()
+	}
+}
+
+type sftpHandler struct {
+	store *Store
+	user  string
+}
+
+func (h *sftpHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	f, ok := h.store.File(h.user, r.Filepath)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return bytes.NewReader(f.Data), nil
+}
+
+func (h *sftpHandler) Filewrite(*sftp.Request) (io.WriterAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+func (h *sftpHandler) Filecmd(*sftp.Request) error {
+	return sftp.ErrSSHFxPermissionDenied
+}
+
+func (h *sftpHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if r.Filepath == "/" {
+		if r.Method != "List" {
+			return listerAt{dirInfo{}}, nil
+		}
+		var entries listerAt
+		for _, f := range h.store.Files(h.user) {
+			entries = append(entries, fileInfo{f})
+		}
+		return entries, nil
+	}
+
+	switch r.Method {
+	case "Stat", "Lstat":
+		f, ok := h.store.File(h.user, r.Filepath)
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return listerAt{fileInfo{f}}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+type listerAt []fs.FileInfo
+
+func (l listerAt) ListAt(dst []fs.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(dst, l[offset:])
+	if n < len(dst) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type fileInfo struct {
+	f File
+}
+
+func (i fileInfo) Name() string       { return i.f.Name }
+func (i fileInfo) Size() int64        { return int64(len(i.f.Data)) }
+func (i fileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i fileInfo) ModTime() time.Time { return i.f.ModTime }
+func (i fileInfo) IsDir() bool        { return false }
+func (i fileInfo) Sys() any           { return nil }
+
+type dirInfo struct{}
+
+func (dirInfo) Name() string       { return "/" }
+func (dirInfo) Size() int64        { return 0 }
+func (dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (dirInfo) ModTime() time.Time { return time.Now() }
+func (dirInfo) IsDir() bool        { return true }
+func (dirInfo) Sys() any           { return nil }
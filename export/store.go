@@ -0,0 +1,115 @@
+package export
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fileTTL is how long an export stays downloadable
+	fileTTL = time.Hour
+	// maxFilesPerUser bounds memory use; the oldest export is dropped first
+	maxFilesPerUser = 10
+	// maxTotalSize bounds the memory of all exports together, since user names are not authenticated
+	maxTotalSize = 256 << 20
+)
+
+// File is an export kept in memory until it is fetched over SCP or SFTP
+type File struct {
+	Name    string
+	Data    []byte
+	ModTime time.Time
+}
+
+// Store keeps recent exports per SSH user. Exports hold public registry data only,
+// so they are keyed by user name rather than by key.
+type Store struct {
+	mu    sync.Mutex
+	files map[string][]File
+}
+
+func NewStore() *Store {
+	return &Store{files: make(map[string][]File)}
+}
+
+// Put stores an export for a user, replacing any export with the same name. Expired exports of
+// every user are dropped, then the oldest ones until all exports fit in maxTotalSize.
+func (s *Store) Put(user, name string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := slices.DeleteFunc(s.live(user), func(f File) bool { return f.Name == name })
+	files = append(files, File{Name: name, Data: data, ModTime: time.Now()})
+	if len(files) > maxFilesPerUser {
+		files = files[len(files)-maxFilesPerUser:]
+	}
+	s.files[user] = files
+
+	s.prune()
+}
+
+// Files lists the exports of a user that have not expired, oldest first
+func (s *Store) Files(user string) []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := s.live(user)
+	if len(files) == 0 {
+		delete(s.files, user)
+		return nil
+	}
+	s.files[user] = files
+	return slices.Clone(files)
+}
+
+// File returns the export with the given name, which may carry a leading slash or ./
+func (s *Store) File(user, name string) (File, bool) {
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "./"), "/")
+	for _, f := range s.Files(user) {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// prune drops the expired exports of all users and evicts the oldest exports while the total
+// size exceeds maxTotalSize; callers must hold the lock
+func (s *Store) prune() {
+	var total int
+	for user := range s.files {
+		files := s.live(user)
+		if len(files) == 0 {
+			delete(s.files, user)
+			continue
+		}
+		s.files[user] = files
+		for _, f := range files {
+			total += len(f.Data)
+		}
+	}
+
+	for total > maxTotalSize {
+		// Files of a user are kept oldest first
+		var oldest string
+		for user, files := range s.files {
+			if oldest == "" || files[0].ModTime.Before(s.files[oldest][0].ModTime) {
+				oldest = user
+			}
+		}
+		total -= len(s.files[oldest][0].Data)
+		if len(s.files[oldest]) == 1 {
+			delete(s.files, oldest)
+		} else {
+			s.files[oldest] = s.files[oldest][1:]
+		}
+	}
+}
+
+// live drops expired exports; callers must hold the lock
+func (s *Store) live(user string) []File {
+	cutoff := time.Now().Add(-fileTTL)
+	return slices.DeleteFunc(s.files[user], func(f File) bool { return f.ModTime.Before(cutoff) })
+}
@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 
+	"github.com/ionut-maxim/goovern"
 	"github.com/ionut-maxim/goovern/ckan"
+	"github.com/ionut-maxim/goovern/db"
 )
 
 type DownloadArgs struct {
@@ -22,24 +25,31 @@ func (args DownloadArgs) Kind() string {
 }
 
 type DownloadWorker struct {
+	db     db.Querier
 	store  ResourceStore
 	jobs   *river.Client[pgx.Tx]
+	repo   progressRepo
 	logger *slog.Logger
 
 	river.WorkerDefaults[DownloadArgs]
 }
 
-func NewDownloadWorker(jobs *river.Client[pgx.Tx], store ResourceStore, logger *slog.Logger) (*DownloadWorker, error) {
+func NewDownloadWorker(jobs *river.Client[pgx.Tx], pool *pgxpool.Pool, store ResourceStore, repo progressRepo, logger *slog.Logger) (*DownloadWorker, error) {
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
 	if store == nil {
 		return nil, errors.New("store required")
 	}
 
 	return &DownloadWorker{
+		db:     pool,
 		store:  store,
 		jobs:   jobs,
+		repo:   repo,
 		logger: logger.With("worker", "download"),
 	}, nil
 }
@@ -79,7 +89,15 @@ func (w *DownloadWorker) Work(ctx context.Context, job *river.Job[DownloadArgs])
 
 	logger.Info("Starting download", "url", resource.Url, "priority", job.Priority)
 
-	if err := w.store.Save(ctx, resource); err != nil {
+	progress := newProgressReporter(ctx, w.db, w.repo, goovern.JobProgress{
+		JobID:        job.ID,
+		Kind:         job.Kind,
+		ResourceName: resource.Name,
+		TotalBytes:   int64(resource.Size),
+	}, logger)
+	defer progress.done()
+
+	if err := w.store.Save(ctx, resource, progress.download); err != nil {
 		logger.Error("Download failed", "error", err)
 		return err
 	}
@@ -8,15 +8,19 @@ import (
 	"os"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 
+	"github.com/ionut-maxim/goovern"
 	"github.com/ionut-maxim/goovern/ckan"
 	"github.com/ionut-maxim/goovern/db"
+	"github.com/ionut-maxim/goovern/metrics"
 )
 
 type ImportArgs struct {
 	Resource ckan.Resource `json:"resourceGetter"`
+	Reload   bool          `json:"reload,omitempty"` // Truncate the table and load the resource into it from scratch
 }
 
 func (i ImportArgs) Kind() string {
@@ -25,11 +29,17 @@ func (i ImportArgs) Kind() string {
 
 type repo interface {
 	SaveResource(ctx context.Context, tx db.Tx, resource ckan.Resource) error
-	Import(ctx context.Context, db db.Tx, resource ckan.Resource, data io.Reader) error
+	Import(ctx context.Context, db db.Tx, pool db.Querier, resource ckan.Resource, data io.Reader, reload bool, progress db.ImportProgress) (db.ImportStats, error)
+	RecordSchemaDrift(ctx context.Context, db db.Querier, drift *db.SchemaDriftError, imported bool) error
+	StartImportRun(ctx context.Context, db db.Querier, resource ckan.Resource, attempt int) (int64, error)
+	FinishImportRun(ctx context.Context, db db.Querier, id int64, stats db.ImportStats, importErr error) error
+	DropStaleStaging(ctx context.Context, db db.Querier, maxAge time.Duration) (int, error)
+	progressRepo
 }
 
 type ImportWorker struct {
 	db     db.Tx
+	jobs   *river.Client[pgx.Tx]
 	repo   repo
 	store  ResourceStore
 	logger *slog.Logger
@@ -37,7 +47,7 @@ type ImportWorker struct {
 	river.WorkerDefaults[ImportArgs]
 }
 
-func NewImportWorker(pool *pgxpool.Pool, store ResourceStore, repo repo, logger *slog.Logger) (*ImportWorker, error) {
+func NewImportWorker(jobs *river.Client[pgx.Tx], pool *pgxpool.Pool, store ResourceStore, repo repo, logger *slog.Logger) (*ImportWorker, error) {
 	if pool == nil {
 		return nil, errors.New("db required")
 	}
@@ -50,13 +60,18 @@ func NewImportWorker(pool *pgxpool.Pool, store ResourceStore, repo repo, logger
 
 	return &ImportWorker{
 		db:     pool,
+		jobs:   jobs,
 		repo:   repo,
 		store:  store,
 		logger: logger.With("worker", "import"),
 	}, nil
 }
 
-func (w *ImportWorker) Timeout(*river.Job[ImportArgs]) time.Duration { return 30 * time.Minute }
+// importTimeout bounds an import, so staging tables older than it were left behind by one that
+// crashed
+const importTimeout = 30 * time.Minute
+
+func (w *ImportWorker) Timeout(*river.Job[ImportArgs]) time.Duration { return importTimeout }
 
 // NextRetry configures retry behavior for failed imports
 func (w *ImportWorker) NextRetry(job *river.Job[ImportArgs]) time.Time {
@@ -77,7 +92,7 @@ func (w *ImportWorker) NextRetry(job *river.Job[ImportArgs]) time.Time {
 	return time.Now().Add(retryIntervals[attempt])
 }
 
-func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportArgs]) error {
+func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportArgs]) (err error) {
 	resource := job.Args.Resource
 	startTime := time.Now()
 
@@ -85,16 +100,45 @@ func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportArgs]) err
 		"resource_id", resource.Id,
 		"resource_name", resource.Name,
 		"attempt", job.Attempt,
+		"reload", job.Args.Reload,
 	)
 
 	logger.Info("Starting import", "priority", job.Priority)
 
+	// Other replicas may be importing, so only tables older than any running import are dropped
+	if _, dropErr := w.repo.DropStaleStaging(ctx, w.db, importTimeout); dropErr != nil {
+		logger.Warn("Failed to drop stale staging tables", "error", dropErr)
+	}
+
+	// The run is recorded outside the import transaction so failed attempts are kept too
+	runID, err := w.repo.StartImportRun(ctx, w.db, resource, job.Attempt)
+	if err != nil {
+		logger.Error("Failed to record import run", "error", err)
+		return err
+	}
+	var stats db.ImportStats
+	defer func() {
+		if finishErr := w.repo.FinishImportRun(context.WithoutCancel(ctx), w.db, runID, stats, err); finishErr != nil {
+			logger.Warn("Failed to record import run outcome", "run_id", runID, "error", finishErr)
+		}
+	}()
+
 	tx, err := w.db.Begin(ctx)
 	if err != nil {
 		logger.Error("Failed to begin transaction", "error", err)
 		return err
 	}
 	defer tx.Rollback(ctx)
+	defer func() {
+		// A parallel copy commits its staging table on another connection. Dropping it there waits
+		// for tx to release it, so tx ends first, committed or not.
+		if stats.StagingTable != "" {
+			tx.Rollback(ctx)
+			if dropErr := db.DropStaging(context.WithoutCancel(ctx), w.db, stats.StagingTable); dropErr != nil {
+				logger.Warn("Failed to drop staging table", "staging_table", stats.StagingTable, "error", dropErr)
+			}
+		}
+	}()
 
 	logger.Debug("Loading file from store")
 	data, err := w.store.Load(ctx, resource)
@@ -104,9 +148,26 @@ func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportArgs]) err
 	}
 	defer data.Close()
 
+	progress := newProgressReporter(ctx, w.db, w.repo, goovern.JobProgress{
+		JobID:        job.ID,
+		Kind:         job.Kind,
+		ResourceName: resource.Name,
+		TotalBytes:   fileSize(data, resource),
+	}, logger)
+	defer progress.done()
+
 	logger.Info("Importing data to database")
-	if err = w.repo.Import(ctx, tx, resource, data); err != nil {
+	if stats, err = w.repo.Import(ctx, tx, w.db, resource, data, job.Args.Reload, progress.update); err != nil {
 		logger.Error("Import failed", "error", err)
+
+		// Retrying cannot fix a format change; it needs a new import config
+		var drift *db.SchemaDriftError
+		if errors.As(err, &drift) {
+			if recordErr := w.repo.RecordSchemaDrift(ctx, w.db, drift, false); recordErr != nil {
+				logger.Warn("Failed to record schema drift", "error", recordErr)
+			}
+			return river.JobCancel(err)
+		}
 		return err
 	}
 
@@ -122,8 +183,30 @@ func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportArgs]) err
 		return err
 	}
 
+	metrics.LastImport.WithLabelValues(resource.Name).SetToCurrentTime()
+
+	// Enqueued after the commit so the refresh is guaranteed to see this import
+	if _, err = w.jobs.Insert(ctx, RefreshStatsArgs{}, nil); err != nil {
+		logger.Warn("Failed to enqueue stats refresh", "error", err)
+	}
+	if stats.IndexesDeferred > 0 {
+		if _, err = w.jobs.Insert(ctx, RebuildIndexesArgs{}, nil); err != nil {
+			logger.Warn("Failed to enqueue index rebuild", "error", err)
+		}
+	}
+
 	duration := time.Since(startTime).Seconds()
 	logger.Info("Import completed successfully", "duration_seconds", duration)
 
 	return nil
 }
+
+// fileSize returns the size of a stored resource, falling back to the size CKAN reported
+func fileSize(data io.Reader, resource ckan.Resource) int64 {
+	if f, ok := data.(interface{ Stat() (os.FileInfo, error) }); ok {
+		if info, err := f.Stat(); err == nil {
+			return info.Size()
+		}
+	}
+	return int64(resource.Size)
+}
@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// RebuildIndexesArgs recreates the indexes dropped by bulk loads; it is enqueued after every
+// bulk load and at startup, in case the process stopped before a rebuild finished
+type RebuildIndexesArgs struct{}
+
+func (RebuildIndexesArgs) Kind() string {
+	return "rebuild_indexes"
+}
+
+type indexRepo interface {
+	RebuildDeferredIndexes(ctx context.Context, pool *pgxpool.Pool) (int, error)
+}
+
+type RebuildIndexesWorker struct {
+	db     *pgxpool.Pool
+	repo   indexRepo
+	logger *slog.Logger
+
+	river.WorkerDefaults[RebuildIndexesArgs]
+}
+
+func NewRebuildIndexesWorker(pool *pgxpool.Pool, repo indexRepo, logger *slog.Logger) (*RebuildIndexesWorker, error) {
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	return &RebuildIndexesWorker{
+		db:     pool,
+		repo:   repo,
+		logger: logger.With("worker", "rebuild_indexes"),
+	}, nil
+}
+
+func (w *RebuildIndexesWorker) Timeout(*river.Job[RebuildIndexesArgs]) time.Duration {
+	return 2 * time.Hour
+}
+
+func (w *RebuildIndexesWorker) Work(ctx context.Context, job *river.Job[RebuildIndexesArgs]) error {
+	startTime := time.Now()
+
+	// Runs on the pool: concurrent index builds cannot run in a transaction
+	rebuilt, err := w.repo.RebuildDeferredIndexes(ctx, w.db)
+	if err != nil {
+		w.logger.Error("Index rebuild failed", "rebuilt", rebuilt, "error", err)
+		return err
+	}
+
+	if rebuilt > 0 {
+		w.logger.Info("Indexes rebuilt", "rebuilt", rebuilt, "duration_seconds", time.Since(startTime).Seconds())
+	}
+	return nil
+}
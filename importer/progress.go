@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ionut-maxim/goovern"
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// progressInterval is how often a running job persists its progress at most
+const progressInterval = 2 * time.Second
+
+type progressRepo interface {
+	SaveJobProgress(ctx context.Context, db db.Querier, progress goovern.JobProgress) error
+	DeleteJobProgress(ctx context.Context, db db.Querier, jobID int64) error
+}
+
+// progressReporter persists the progress of one job outside its transaction, so it is
+// visible while the job runs
+type progressReporter struct {
+	ctx      context.Context
+	db       db.Querier
+	repo     progressRepo
+	progress goovern.JobProgress
+	savedAt  time.Time
+	logger   *slog.Logger
+}
+
+func newProgressReporter(ctx context.Context, db db.Querier, repo progressRepo, progress goovern.JobProgress, logger *slog.Logger) *progressReporter {
+	p := &progressReporter{
+		ctx:      ctx,
+		db:       db,
+		repo:     repo,
+		progress: progress,
+		logger:   logger,
+	}
+	p.save()
+	return p
+}
+
+// update records the rows and bytes processed so far, saving them once progressInterval has passed
+func (p *progressReporter) update(rows, bytes int64) {
+	p.progress.Rows = rows
+	p.progress.Bytes = bytes
+	if time.Since(p.savedAt) >= progressInterval {
+		p.save()
+	}
+}
+
+// download adapts the reporter to a DownloadProgress callback
+func (p *progressReporter) download(downloaded, total int64) {
+	if total > 0 {
+		p.progress.TotalBytes = total
+	}
+	p.update(0, downloaded)
+}
+
+func (p *progressReporter) save() {
+	p.savedAt = time.Now()
+	if err := p.repo.SaveJobProgress(p.ctx, p.db, p.progress); err != nil {
+		p.logger.Warn("Failed to save job progress", "job_id", p.progress.JobID, "error", err)
+	}
+}
+
+// done removes the progress once the job returned, whatever its outcome
+func (p *progressReporter) done() {
+	if err := p.repo.DeleteJobProgress(context.WithoutCancel(p.ctx), p.db, p.progress.JobID); err != nil {
+		p.logger.Warn("Failed to delete job progress", "job_id", p.progress.JobID, "error", err)
+	}
+}
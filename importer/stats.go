@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+
+	"github.com/ionut-maxim/goovern/db"
+)
+
+// RefreshStatsArgs recomputes the registry statistics; it is enqueued after every import
+type RefreshStatsArgs struct{}
+
+func (RefreshStatsArgs) Kind() string {
+	return "refresh_stats"
+}
+
+type statsRepo interface {
+	RefreshStats(ctx context.Context, db db.Tx, since time.Time) (bool, error)
+}
+
+type RefreshStatsWorker struct {
+	db     db.Tx
+	repo   statsRepo
+	logger *slog.Logger
+
+	river.WorkerDefaults[RefreshStatsArgs]
+}
+
+func NewRefreshStatsWorker(pool *pgxpool.Pool, repo statsRepo, logger *slog.Logger) (*RefreshStatsWorker, error) {
+	if pool == nil {
+		return nil, errors.New("db required")
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	return &RefreshStatsWorker{
+		db:     pool,
+		repo:   repo,
+		logger: logger.With("worker", "refresh_stats"),
+	}, nil
+}
+
+func (w *RefreshStatsWorker) Timeout(*river.Job[RefreshStatsArgs]) time.Duration {
+	return 30 * time.Minute
+}
+
+func (w *RefreshStatsWorker) Work(ctx context.Context, job *river.Job[RefreshStatsArgs]) error {
+	startTime := time.Now()
+
+	// A refresh that started after this job was enqueued already covers its import
+	refreshed, err := w.repo.RefreshStats(ctx, w.db, job.CreatedAt)
+	if err != nil {
+		w.logger.Error("Stats refresh failed", "error", err)
+		return err
+	}
+
+	if !refreshed {
+		w.logger.Debug("Stats already refreshed since job was enqueued")
+		return nil
+	}
+
+	w.logger.Info("Stats refreshed", "duration_seconds", time.Since(startTime).Seconds())
+	return nil
+}
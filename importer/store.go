@@ -14,8 +14,12 @@ import (
 	"github.com/ionut-maxim/goovern/ckan"
 )
 
+// DownloadProgress is called periodically during a download with the bytes on disk so far and
+// the expected size, 0 when unknown
+type DownloadProgress func(downloaded, total int64)
+
 type ResourceStore interface {
-	Save(ctx context.Context, resource ckan.Resource) error
+	Save(ctx context.Context, resource ckan.Resource, progress DownloadProgress) error
 	Load(ctx context.Context, resource ckan.Resource) (io.ReadCloser, error)
 }
 
@@ -34,7 +38,7 @@ func NewFSResourceStore(path string, logger *slog.Logger) (*FSResourceStore, err
 	}, nil
 }
 
-func (s *FSResourceStore) Save(ctx context.Context, resource ckan.Resource) error {
+func (s *FSResourceStore) Save(ctx context.Context, resource ckan.Resource, progress DownloadProgress) error {
 	logger := s.logger.With("resource_id", resource.Id, "resource_name", resource.Name)
 
 	if !resource.PackageId.Valid {
@@ -128,8 +132,17 @@ func (s *FSResourceStore) Save(ctx context.Context, resource ckan.Resource) erro
 	}
 	defer file.Close()
 
+	// A partial response only carries the remaining bytes
+	total := int64(resource.Size)
+	if resp.ContentLength > 0 {
+		total = resp.ContentLength
+		if resp.StatusCode == http.StatusPartialContent {
+			total += existingSize
+		}
+	}
+
 	// Use a context-aware copy that can be cancelled
-	if err = copyWithContext(ctx, file, resp.Body, existingSize, s.logger.With("resource_name", resource.Name, "resource_id", resource.Id)); err != nil {
+	if err = copyWithContext(ctx, file, resp.Body, existingSize, total, progress, s.logger.With("resource_name", resource.Name, "resource_id", resource.Id)); err != nil {
 		// Leave partial file in place for resume on next retry
 		return err
 	}
@@ -152,7 +165,8 @@ func (s *FSResourceStore) Load(ctx context.Context, resource ckan.Resource) (io.
 
 // copyWithContext copies from src to dst while respecting context cancellation
 // existingSize is the number of bytes already downloaded (for resume tracking)
-func copyWithContext(ctx context.Context, dst io.Writer, src io.Reader, existingSize int64, logger *slog.Logger) error {
+// progress, if set, is called after every chunk with the bytes on disk and the expected total
+func copyWithContext(ctx context.Context, dst io.Writer, src io.Reader, existingSize, total int64, progress DownloadProgress, logger *slog.Logger) error {
 	size := 10 * humanize.MByte // 10MB buffer
 	buf := make([]byte, size)
 	var totalWritten int64
@@ -181,6 +195,9 @@ func copyWithContext(ctx context.Context, dst io.Writer, src io.Reader, existing
 				return io.ErrShortWrite
 			}
 			totalWritten += int64(nw)
+			if progress != nil {
+				progress(existingSize+totalWritten, total)
+			}
 
 			// Only log every 10 MB
 			if totalWritten-lastLoggedAt >= logInterval {
@@ -210,7 +227,7 @@ func NewNoopResourceStore(logger *slog.Logger) *NoopResourceStore {
 	return &NoopResourceStore{logger: logger.With("store", "noop")}
 }
 
-func (s *NoopResourceStore) Save(ctx context.Context, resource ckan.Resource) error {
+func (s *NoopResourceStore) Save(ctx context.Context, resource ckan.Resource, progress DownloadProgress) error {
 	s.logger.Info("Saving resourceGetter", "package_id", resource.PackageId, "resource_name", resource.Name)
 	return nil
 }
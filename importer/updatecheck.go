@@ -3,8 +3,11 @@ package importer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,6 +29,13 @@ func (u UpdateCheckArgs) Kind() string {
 
 type resourceGetter interface {
 	Resource(ctx context.Context, tx db.Tx, id uuid.UUID) (ckan.Resource, bool, error)
+	SaveResource(ctx context.Context, tx db.Tx, resource ckan.Resource) error
+}
+
+// importPlanner orders resources by their import dependencies
+type importPlanner interface {
+	ImportTiers(names []string) [][]string
+	FullReload(names []string) bool
 }
 
 type UpdatesWorker struct {
@@ -35,11 +45,12 @@ type UpdatesWorker struct {
 	db         db.Tx
 	store      ResourceStore
 	resource   resourceGetter
+	planner    importPlanner
 
 	river.WorkerDefaults[UpdateCheckArgs]
 }
 
-func NewUpdatesWorker(jobs *river.Client[pgx.Tx], db db.Tx, res resourceGetter, logger *slog.Logger) (*UpdatesWorker, error) {
+func NewUpdatesWorker(jobs *river.Client[pgx.Tx], db db.Tx, res resourceGetter, planner importPlanner, logger *slog.Logger) (*UpdatesWorker, error) {
 	client, err := ckan.New()
 	if err != nil {
 		return nil, err
@@ -58,11 +69,16 @@ func NewUpdatesWorker(jobs *river.Client[pgx.Tx], db db.Tx, res resourceGetter,
 		return nil, errors.New("resourceGetter required")
 	}
 
+	if planner == nil {
+		return nil, errors.New("importPlanner required")
+	}
+
 	return &UpdatesWorker{
 		ckanClient: client,
 		jobs:       jobs,
 		db:         db,
 		resource:   res,
+		planner:    planner,
 		logger:     logger.With("worker", "updates"),
 	}, nil
 }
@@ -73,20 +89,12 @@ func (w *UpdatesWorker) Timeout(*river.Job[UpdateCheckArgs]) time.Duration {
 }
 
 //CSV Import Order
-//Tier 1 - No dependencies (import first):
-//
-//N_VERSIUNE_CAEN.CSV → caen_versions table
-//N_STARE_FIRMA.CSV → company_statuses table
-//Tier 2 - Depends on Tier 1:
-//3. N_CAEN.CSV → caen_codes table (requires caen_versions)
-//4. OD_FIRME.CSV → companies table (no dependencies)
+//The depends_on entries of the import configs (db/imports.yaml) decide the order.
+//Each tier only depends on earlier ones, e.g. with the embedded configs:
 //
-//Tier 3 - Depends on Tier 2:
-//5. OD_CAEN_AUTORIZAT.CSV → authorized_activities table (requires companies + caen_versions)
-//6. OD_STARE_FIRMA.CSV → company_status_history table (requires companies + company_statuses)
-//7. OD_REPREZENTANTI_LEGALI.CSV → legal_representatives table (requires companies)
-//8. OD_REPREZENTANTI_IF.CSV → family_business_representatives table (requires companies)
-//9. OD_SUCURSALE_ALTE_STATE_MEMBRE.CSV → foreign_branches table (requires companies)
+//Tier 1: N_VERSIUNE_CAEN.CSV, N_STARE_FIRMA.CSV, OD_FIRME.CSV
+//Tier 2: N_CAEN.CSV, OD_CAEN_AUTORIZAT.CSV, OD_STARE_FIRMA.CSV, OD_REPREZENTANTI_*.CSV,
+//        OD_SUCURSALE_ALTE_STATE_MEMBRE.CSV
 
 func (w *UpdatesWorker) Work(ctx context.Context, _ *river.Job[UpdateCheckArgs]) error {
 	startTime := time.Now()
@@ -129,6 +137,21 @@ func (w *UpdatesWorker) Work(ctx context.Context, _ *river.Job[UpdateCheckArgs])
 		return nil
 	}
 
+	// A full snapshot reloads every table from its newest resource; older ones are only recorded
+	reload := w.planner.FullReload(resourceNames(newResources))
+	if reload {
+		var superseded []ckan.Resource
+		newResources, superseded = latestResources(newResources)
+		for _, resource := range superseded {
+			logger.Info("Skipping superseded resource", "resource_id", resource.Id, "resource_name", resource.Name)
+			if err = w.resource.SaveResource(ctx, w.db, resource); err != nil {
+				logger.Error("Failed to save superseded resource", "resource_id", resource.Id, "error", err)
+				return err
+			}
+		}
+		logger.Info("New snapshot of every resource found, reloading all tables", "resources", len(newResources))
+	}
+
 	logger.Info("Starting download phase", "total_resources", len(newResources))
 
 	// Schedule all downloads in parallel
@@ -172,7 +195,7 @@ func (w *UpdatesWorker) Work(ctx context.Context, _ *river.Job[UpdateCheckArgs])
 
 	// Now schedule imports in the correct order
 	logger.Info("Starting import phase")
-	if err = w.scheduleImports(ctx, newResources); err != nil {
+	if err = w.scheduleImports(ctx, newResources, reload); err != nil {
 		logger.Error("Failed to schedule imports", "error", err)
 		return err
 	}
@@ -227,38 +250,64 @@ func (w *UpdatesWorker) waitForJobs(ctx context.Context, jobs []*rivertype.JobIn
 	}
 }
 
-// scheduleImports schedules import jobs in the correct order based on dependencies
-func (w *UpdatesWorker) scheduleImports(ctx context.Context, resources []ckan.Resource) error {
-	// Group resources by priority tier
-	var tier1 []ckan.Resource // N_VERSIUNE_CAEN.CSV, N_STARE_FIRMA.CSV
-	var tier2 []ckan.Resource // N_CAEN.CSV
-	var tier3 []ckan.Resource // OD_FIRME.CSV
-	var tier4 []ckan.Resource // Everything else
+// resourceNames lists the distinct names of resources
+func resourceNames(resources []ckan.Resource) []string {
+	var names []string
+	for _, resource := range resources {
+		if !slices.Contains(names, resource.Name) {
+			names = append(names, resource.Name)
+		}
+	}
+	return names
+}
 
+// latestResources keeps the most recently created resource of each name, returning the older
+// ones separately
+func latestResources(resources []ckan.Resource) (latest, superseded []ckan.Resource) {
+	newest := make(map[string]int)
 	for _, resource := range resources {
-		switch resource.Name {
-		case "N_VERSIUNE_CAEN.CSV", "N_STARE_FIRMA.CSV":
-			tier1 = append(tier1, resource)
-		case "N_CAEN.CSV":
-			tier2 = append(tier2, resource)
-		case "OD_FIRME.CSV":
-			tier3 = append(tier3, resource)
+		i, ok := newest[resource.Name]
+		switch {
+		case !ok:
+			newest[resource.Name] = len(latest)
+			latest = append(latest, resource)
+		case resource.Created.After(latest[i].Created.Time):
+			superseded = append(superseded, latest[i])
+			latest[i] = resource
 		default:
-			tier4 = append(tier4, resource)
+			superseded = append(superseded, resource)
 		}
 	}
+	return latest, superseded
+}
 
-	// Schedule and wait for each tier to complete before moving to next
-	tiers := []struct {
+// scheduleImports schedules import jobs in the correct order based on dependencies. A reload
+// truncates each table before importing into it.
+func (w *UpdatesWorker) scheduleImports(ctx context.Context, resources []ckan.Resource, reload bool) error {
+	// Group resources by dependency tier; several resources may share a name across packages
+	byName := make(map[string][]ckan.Resource)
+	var names []string
+	for _, resource := range resources {
+		if _, ok := byName[resource.Name]; !ok {
+			names = append(names, resource.Name)
+		}
+		byName[resource.Name] = append(byName[resource.Name], resource)
+	}
+
+	type tier struct {
 		name      string
 		resources []ckan.Resource
-	}{
-		{"Tier 1 (caen_versions, company_statuses)", tier1},
-		{"Tier 2 (caen_codes)", tier2},
-		{"Tier 3 (companies)", tier3},
-		{"Tier 4 (dependent tables)", tier4},
+	}
+	var tiers []tier
+	for i, tierNames := range w.planner.ImportTiers(names) {
+		t := tier{name: fmt.Sprintf("Tier %d (%s)", i+1, strings.Join(tierNames, ", "))}
+		for _, name := range tierNames {
+			t.resources = append(t.resources, byName[name]...)
+		}
+		tiers = append(tiers, t)
 	}
 
+	// Schedule and wait for each tier to complete before moving to next
 	for _, tier := range tiers {
 		if len(tier.resources) == 0 {
 			w.logger.Debug("Skipping empty tier", "tier", tier.name)
@@ -276,7 +325,7 @@ func (w *UpdatesWorker) scheduleImports(ctx context.Context, resources []ckan.Re
 		var importJobs []river.InsertManyParams
 		for _, resource := range tier.resources {
 			importJobs = append(importJobs, river.InsertManyParams{
-				Args:       ImportArgs{Resource: resource},
+				Args:       ImportArgs{Resource: resource, Reload: reload},
 				InsertOpts: &river.InsertOpts{},
 			})
 		}
@@ -0,0 +1,40 @@
+package goovern
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportRun is one attempt at importing an ONRC resource
+type ImportRun struct {
+	ID              int64         `json:"id" db:"id"`
+	ResourceID      uuid.UUID     `json:"resource_id" db:"resource_id"`
+	PackageID       uuid.NullUUID `json:"package_id" db:"package_id"`
+	ResourceName    string        `json:"resource_name" db:"resource_name"`
+	Attempt         int           `json:"attempt" db:"attempt"`
+	Status          string        `json:"status" db:"status"` // running, succeeded or failed
+	StartedAt       time.Time     `json:"started_at" db:"started_at"`
+	FinishedAt      *time.Time    `json:"finished_at,omitempty" db:"finished_at"`
+	DurationSeconds float64       `json:"duration_seconds" db:"duration_seconds"`
+	RowsRead        int64         `json:"rows_read" db:"rows_read"`
+	RowsInserted    int64         `json:"rows_inserted" db:"rows_inserted"`
+	RowsRejected    int64         `json:"rows_rejected" db:"rows_rejected"`
+	Bytes           int64         `json:"bytes" db:"bytes"`
+	Error           string        `json:"error,omitempty" db:"error"`
+}
+
+// JobProgress is the latest reported progress of a running background job. Jobs that have
+// not reported yet, or do not report at all, have zero counts.
+type JobProgress struct {
+	JobID        int64      `json:"job_id" db:"job_id"`
+	Kind         string     `json:"kind" db:"kind"`
+	ResourceName string     `json:"resource_name" db:"resource_name"`
+	Attempt      int        `json:"attempt" db:"attempt"`
+	StartedAt    time.Time  `json:"started_at" db:"started_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+	Rows         int64      `json:"rows" db:"rows"`
+	Bytes        int64      `json:"bytes" db:"bytes"`
+	TotalBytes   int64      `json:"total_bytes" db:"total_bytes"` // 0 when the size is unknown
+	ETASeconds   *float64   `json:"eta_seconds,omitempty" db:"eta_seconds"`
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goovern"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// SearchQueries counts searches, labelled by what was searched (company, person) and outcome (ok, error)
+	SearchQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "search_queries_total",
+		Help:      "Number of search queries executed against the registry.",
+	}, []string{"target", "outcome"})
+
+	// SSHSessions counts SSH sessions opened against the server
+	SSHSessions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ssh_sessions_total",
+		Help:      "Number of SSH sessions opened.",
+	})
+
+	// JobOutcomes counts River job events per job kind and outcome
+	JobOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_total",
+		Help:      "Number of River job outcomes per job kind.",
+	}, []string{"kind", "outcome"})
+
+	// LastImport holds the unix timestamp of the last successful import per resource
+	LastImport = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_import_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful import per resource.",
+	}, []string{"resource"})
+
+	// SchemaDrift counts imports whose CSV headers did not match the target table, per resource
+	SchemaDrift = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "import_schema_drift_total",
+		Help:      "Number of imports whose CSV headers did not match the target table.",
+	}, []string{"resource"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		SearchQueries,
+		SSHSessions,
+		JobOutcomes,
+		LastImport,
+		SchemaDrift,
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
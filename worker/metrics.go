@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"github.com/ionut-maxim/goovern/metrics"
+)
+
+// ObserveJobs records River job outcomes per kind until the returned cancel function is called
+func ObserveJobs(client *river.Client[pgx.Tx]) func() {
+	events, cancel := client.Subscribe(
+		river.EventKindJobCompleted,
+		river.EventKindJobFailed,
+		river.EventKindJobCancelled,
+		river.EventKindJobSnoozed,
+	)
+
+	go func() {
+		for event := range events {
+			if event.Job == nil {
+				continue
+			}
+			metrics.JobOutcomes.WithLabelValues(event.Job.Kind, strings.TrimPrefix(string(event.Kind), "job_")).Inc()
+		}
+	}()
+
+	return cancel
+}
@@ -25,17 +25,27 @@ func New(pool *pgxpool.Pool, db *db.DB, logger *slog.Logger) (*river.Client[pgx.
 		return nil, err
 	}
 
-	updatesWorker, err := importer.NewUpdatesWorker(jobsClient, pool, db, logger)
+	updatesWorker, err := importer.NewUpdatesWorker(jobsClient, pool, db, db, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	downloadWorker, err := importer.NewDownloadWorker(jobsClient, resourceStore, logger)
+	downloadWorker, err := importer.NewDownloadWorker(jobsClient, pool, resourceStore, db, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	importWorker, err := importer.NewImportWorker(pool, resourceStore, db, logger)
+	importWorker, err := importer.NewImportWorker(jobsClient, pool, resourceStore, db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	statsWorker, err := importer.NewRefreshStatsWorker(pool, db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	indexesWorker, err := importer.NewRebuildIndexesWorker(pool, db, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +54,8 @@ func New(pool *pgxpool.Pool, db *db.DB, logger *slog.Logger) (*river.Client[pgx.
 	river.AddWorker(workers, updatesWorker)
 	river.AddWorker(workers, downloadWorker)
 	river.AddWorker(workers, importWorker)
+	river.AddWorker(workers, statsWorker)
+	river.AddWorker(workers, indexesWorker)
 
 	schedule, err := cron.ParseStandard("@midnight")
 	if err != nil {
@@ -58,6 +70,13 @@ func New(pool *pgxpool.Pool, db *db.DB, logger *slog.Logger) (*river.Client[pgx.
 			},
 			&river.PeriodicJobOpts{RunOnStart: true, ID: "update-checker"},
 		),
+		river.NewPeriodicJob(
+			schedule,
+			func() (river.JobArgs, *river.InsertOpts) {
+				return importer.RebuildIndexesArgs{}, &river.InsertOpts{}
+			},
+			&river.PeriodicJobOpts{RunOnStart: true, ID: "index-rebuilder"},
+		),
 	}
 
 	workClient, err := river.NewClient(riverpgxv5.New(pool), &river.Config{